@@ -0,0 +1,83 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"ai-video-editor/processing/store"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	pruneOlderThan time.Duration
+	pruneMissing   bool
+	pruneAll       bool
+)
+
+var cacheCmd = &cobra.Command{
+	Use:   "cache",
+	Short: "Manage the analysis cache",
+	Long: `The analysis cache stores probe results and transcripts per video, keyed by the
+file's content and the transcription model, so re-running process on the same
+video with a different prompt skips audio extraction and transcription.`,
+}
+
+var cachePruneCmd = &cobra.Command{
+	Use:   "prune",
+	Short: "Remove old or orphaned cache entries",
+	Long: `Prune removes analysis cache entries older than --older-than. With --missing it
+also removes entries whose source video no longer exists, and --all empties the
+cache entirely.`,
+	Example: `  # Remove entries older than 30 days (the default)
+  ai-editor cache prune
+
+  # Remove entries older than a week or for deleted videos
+  ai-editor cache prune --older-than 168h --missing
+
+  # Empty the cache
+  ai-editor cache prune --all`,
+	RunE: runCachePrune,
+}
+
+func init() {
+	rootCmd.AddCommand(cacheCmd)
+	cacheCmd.AddCommand(cachePruneCmd)
+
+	cachePruneCmd.Flags().DurationVar(&pruneOlderThan, "older-than", 30*24*time.Hour, "remove entries created longer ago than this (0 to ignore age)")
+	cachePruneCmd.Flags().BoolVar(&pruneMissing, "missing", false, "remove entries whose source video no longer exists")
+	cachePruneCmd.Flags().BoolVar(&pruneAll, "all", false, "remove every entry")
+}
+
+func runCachePrune(cmd *cobra.Command, args []string) error {
+	db, err := store.Open(store.DefaultPath())
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	removed, err := db.PruneAnalysis(context.Background(), store.PruneOptions{
+		OlderThan: pruneOlderThan,
+		Missing:   pruneMissing,
+		All:       pruneAll,
+	})
+	if err != nil {
+		return err
+	}
+
+	if structuredOutput() {
+		return writeStructured(struct {
+			Removed int64 `json:"removed"`
+		}{removed})
+	}
+	fmt.Fprintf(humanOut(), "🧹 Removed %d cache entr%s\n", removed, plural(removed, "y", "ies"))
+	return nil
+}
+
+func plural(n int64, one, many string) string {
+	if n == 1 {
+		return one
+	}
+	return many
+}
@@ -4,6 +4,14 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"ai-video-editor/processing/ai"
+	"ai-video-editor/processing/analysis"
+	"ai-video-editor/processing/captions"
+	"ai-video-editor/processing/video"
 
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
@@ -22,11 +30,37 @@ var configSetCmd = &cobra.Command{
 	Long: `Set configuration values such as API keys and default processing parameters.
 
 Available configuration keys:
-  api-key          OpenAI API key for AI analysis
-  whisper-model    Whisper model size (tiny, base, small, medium, large)
+  api-key          Hugging Face API key for transcription and AI analysis
+  whisper-model    Transcription model: a size (tiny, base, small, medium, large)
+                   or <backend>:<model> with backend hf, openai or local
+                   (e.g. hf:openai/whisper-small, openai:whisper-1, local:base)
   default-duration Default clip duration
   default-quality  Default output quality (low, medium, high)
-  temp-dir         Temporary directory for processing`,
+  default-caption-style  Default style for burned-in captions (bold-center, lower-third, minimal)
+  temp-dir         Temporary directory for processing
+  ffmpeg-path      Path to the ffmpeg binary (default: auto-detect)
+  ffprobe-path     Path to the ffprobe binary (default: auto-detect)
+  chunk-length     Audio chunk length for transcription (default: 5m)
+  chunk-overlap    Overlap between audio chunks (default: 2s)
+  caption-line-length  Maximum characters per caption line (default: 42)
+  caption-lines    Maximum lines per caption (default: 2)
+  caption-cps      Maximum caption reading speed in characters/sec (default: 17)
+  scene-threshold  Scene change sensitivity from 0 to 1, 0 to skip detection (default: 0.3)
+  language         Spoken language hint for transcription (e.g. en)
+  openai-api-key   API key for the OpenAI-compatible Whisper backend
+  whisper-api-url  Base URL of an OpenAI-compatible Whisper server
+  whisper-cpp-path Path to the whisper.cpp binary (default: auto-detect)
+  whisper-cpp-model-dir  Directory containing ggml-<size>.bin models
+  db-path          Job database file (default: ~/.ai-editor/ai-editor.db)
+
+Quality presets (low, medium, high) can be tuned per key, and new presets
+defined, with quality-presets.<name>.<field> where field is one of:
+  codec, crf, preset, max-bitrate, audio-codec, audio-bitrate, max-height
+
+Caption styles (bold-center, lower-third, minimal) work the same way with
+caption-styles.<name>.<field> where field is one of:
+  font, size, bold, color, highlight-color, outline-color, outline, shadow,
+  position, margin, max-words`,
 	Args: cobra.ExactArgs(2),
 	Example: `  # Set OpenAI API key
   ai-editor config set api-key sk-your-openai-key-here
@@ -35,7 +69,17 @@ Available configuration keys:
   ai-editor config set default-duration 45s
   
   # Set Whisper model size
-  ai-editor config set whisper-model medium`,
+  ai-editor config set whisper-model medium
+
+  # Transcribe locally with whisper.cpp
+  ai-editor config set whisper-model local:base
+
+  # Keep the high quality preset at full resolution but lower its CRF
+  ai-editor config set quality-presets.high.crf 16
+
+  # Define a caption style in your brand color and use it by default
+  ai-editor config set caption-styles.brand.highlight-color "#FF0050"
+  ai-editor config set default-caption-style brand`,
 	RunE: runConfigSet,
 }
 
@@ -78,14 +122,19 @@ func runConfigSet(cmd *cobra.Command, args []string) error {
 	key := args[0]
 	value := args[1]
 
-	// Validate key
-	if err := validateConfigKey(key); err != nil {
+	// Validate key and value
+	if err := validateConfigValue(key, value); err != nil {
 		return err
 	}
 
 	// Set the value
 	viper.Set(key, value)
 
+	// Reject preset fields and caption limits that would leave the setting unusable
+	if err := checkConfigKey(key); err != nil {
+		return err
+	}
+
 	// Ensure config directory exists
 	configDir := filepath.Dir(viper.ConfigFileUsed())
 	if configDir == "" {
@@ -109,6 +158,9 @@ func runConfigSet(cmd *cobra.Command, args []string) error {
 		}
 	}
 
+	if structuredOutput() {
+		return writeStructured(configValue{Key: key, Value: displayConfigValue(key, value), Set: true})
+	}
 	fmt.Printf("✅ Configuration updated: %s = %s\n", key, value)
 	return nil
 }
@@ -116,40 +168,54 @@ func runConfigSet(cmd *cobra.Command, args []string) error {
 func runConfigGet(cmd *cobra.Command, args []string) error {
 	key := args[0]
 	value := viper.GetString(key)
-	
+
+	if structuredOutput() {
+		return writeStructured(configValue{Key: key, Value: displayConfigValue(key, value), Set: value != ""})
+	}
+
 	if value == "" {
 		fmt.Printf("❌ Configuration key '%s' not found or empty\n", key)
 		return nil
 	}
 
 	// Hide sensitive values
-	if key == "api-key" {
-		if len(value) > 8 {
-			value = value[:4] + "..." + value[len(value)-4:]
-		}
-	}
-
-	fmt.Printf("%s = %s\n", key, value)
+	fmt.Printf("%s = %s\n", key, displayConfigValue(key, value))
 	return nil
 }
 
 func runConfigList(cmd *cobra.Command, args []string) error {
+	if structuredOutput() {
+		return writeStructured(newConfigListing())
+	}
+
 	fmt.Println("Current configuration:")
 	fmt.Println()
 
-	settings := map[string]string{
-		"hugging-face-api-key":          viper.GetString("api-key"),
+	for _, k := range configKeys {
+		if !viper.IsSet(k.name) {
+			continue
+		}
+		fmt.Printf("  %-22s = %s\n", k.name, displayConfigValue(k.name, viper.GetString(k.name)))
+	}
+
+	fmt.Println("\nQuality presets:")
+	for _, name := range video.QualityNames() {
+		settings, err := video.QualityPreset(name)
+		if err != nil {
+			fmt.Printf("  %-14s ❌ %v\n", name, err)
+			continue
+		}
+		fmt.Printf("  %-14s %s crf %d, %s, max %s\n", name, settings.VideoCodec, settings.CRF, settings.Preset, orDefault(settings.MaxBitrate, "no bitrate cap"))
 	}
 
-	for key, value := range settings {
-		displayValue := value
-		if value == "" {
-			displayValue = "(not set)"
-		} else if key == "api-key" && len(value) > 8 {
-			displayValue = value[:4] + "..." + value[len(value)-4:]
+	fmt.Println("\nCaption styles:")
+	for _, name := range captions.StyleNames() {
+		style, err := captions.StylePreset(name)
+		if err != nil {
+			fmt.Printf("  %-14s ❌ %v\n", name, err)
+			continue
 		}
-		
-		fmt.Printf("  %-16s = %s\n", key, displayValue)
+		fmt.Printf("  %-14s %s %dpx, %s, %s highlight, %d words per line\n", name, style.Font, style.Size, style.Position, style.HighlightColor, style.MaxWordsPerLine)
 	}
 
 	configFile := viper.ConfigFileUsed()
@@ -160,13 +226,73 @@ func runConfigList(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// configValue is a setting as reported by --output-format json and ndjson
+type configValue struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+	Set   bool   `json:"set"`
+}
+
+// configListing is config list as reported by --output-format json and ndjson
+type configListing struct {
+	Settings       map[string]string `json:"settings"`
+	QualityPresets []presetInfo      `json:"quality_presets"`
+	CaptionStyles  []presetInfo      `json:"caption_styles"`
+	ConfigFile     string            `json:"config_file,omitempty"`
+}
+
+// presetInfo is a named preset's settings, or why it can't be used
+type presetInfo struct {
+	Name     string `json:"name"`
+	Settings any    `json:"settings,omitempty"`
+	Error    string `json:"error,omitempty"`
+}
+
+func newConfigListing() configListing {
+	listing := configListing{Settings: map[string]string{}, ConfigFile: viper.ConfigFileUsed()}
+	for _, k := range configKeys {
+		if viper.IsSet(k.name) {
+			listing.Settings[k.name] = displayConfigValue(k.name, viper.GetString(k.name))
+		}
+	}
+	for _, name := range video.QualityNames() {
+		settings, err := video.QualityPreset(name)
+		listing.QualityPresets = append(listing.QualityPresets, newPresetInfo(name, settings, err))
+	}
+	for _, name := range captions.StyleNames() {
+		style, err := captions.StylePreset(name)
+		listing.CaptionStyles = append(listing.CaptionStyles, newPresetInfo(name, style, err))
+	}
+	return listing
+}
+
+func newPresetInfo(name string, settings any, err error) presetInfo {
+	if err != nil {
+		return presetInfo{Name: name, Error: err.Error()}
+	}
+	return presetInfo{Name: name, Settings: settings}
+}
+
+// displayConfigValue hides most of a secret value
+func displayConfigValue(key, value string) string {
+	if strings.HasSuffix(key, "api-key") && len(value) > 8 {
+		return value[:4] + "..." + value[len(value)-4:]
+	}
+	return value
+}
+
 func runConfigReset(cmd *cobra.Command, args []string) error {
-	fmt.Print("⚠️  This will reset all configuration to defaults. Continue? (y/N): ")
+	fmt.Fprint(humanOut(), "⚠️  This will reset all configuration to defaults. Continue? (y/N): ")
 	
 	var response string
 	fmt.Scanln(&response)
 	
 	if response != "y" && response != "Y" {
+		if structuredOutput() {
+			return writeStructured(struct {
+				Reset bool `json:"reset"`
+			}{false})
+		}
 		fmt.Println("Configuration reset cancelled.")
 		return nil
 	}
@@ -178,24 +304,158 @@ func runConfigReset(cmd *cobra.Command, args []string) error {
 		}
 	}
 
+	if structuredOutput() {
+		return writeStructured(struct {
+			Reset bool `json:"reset"`
+		}{true})
+	}
 	fmt.Println("✅ Configuration reset to defaults.")
 	return nil
 }
 
+// configKey is a top-level setting accepted by config set
+type configKey struct {
+	name     string
+	validate func(value string) error // nil accepts any value
+}
+
+var configKeys = []configKey{
+	{"api-key", nil},
+	{"whisper-model", func(v string) error { _, _, err := ai.ParseModelSpec(v); return err }},
+	{"default-duration", func(v string) error { _, err := analysis.ParseDurationSpec(v); return err }},
+	{"default-quality", func(v string) error { _, err := video.QualityPreset(v); return err }},
+	{"default-caption-style", func(v string) error { _, err := captions.StylePreset(v); return err }},
+	{"temp-dir", nil},
+	{"ffmpeg-path", nil},
+	{"ffprobe-path", nil},
+	{"chunk-length", validateDuration},
+	{"chunk-overlap", validateDuration},
+	{"caption-line-length", validateInt},
+	{"caption-lines", validateInt},
+	{"caption-cps", validateFloat},
+	{"scene-threshold", validateSceneThreshold},
+	{"language", nil},
+	{"openai-api-key", nil},
+	{"whisper-api-url", nil},
+	{"whisper-cpp-path", nil},
+	{"whisper-cpp-model-dir", nil},
+	{"db-path", nil},
+}
+
+// configSection is a config map of named presets, each set field by field
+// with <section>.<name>.<field>
+type configSection struct {
+	key    string
+	fields []string
+	check  func(name string) error // Validates a preset once a field has changed
+}
+
+var configSections = []configSection{
+	{video.QualityPresetsKey, video.EncodeSettingFields, func(name string) error {
+		_, err := video.QualityPreset(name)
+		return err
+	}},
+	{captions.CaptionStylesKey, captions.StyleFields, func(name string) error {
+		_, err := captions.StylePreset(name)
+		return err
+	}},
+}
+
 func validateConfigKey(key string) error {
-	validKeys := []string{
-		"api-key",
-		"whisper-model", 
-		"default-duration",
-		"default-quality",
-		"temp-dir",
+	if _, ok := findConfigKey(key); ok {
+		return nil
+	}
+	if _, _, ok := findConfigSection(key); ok {
+		return nil
+	}
+
+	names := make([]string, len(configKeys))
+	for i, k := range configKeys {
+		names[i] = k.name
+	}
+	return fmt.Errorf("invalid configuration key: %s (valid keys: %s, or quality-presets.<name>.<field> and caption-styles.<name>.<field>)", key, strings.Join(names, ", "))
+}
+
+// validateConfigValue checks the key and, for top-level settings, the value
+func validateConfigValue(key, value string) error {
+	if err := validateConfigKey(key); err != nil {
+		return err
+	}
+	if k, ok := findConfigKey(key); ok && k.validate != nil {
+		if err := k.validate(value); err != nil {
+			return fmt.Errorf("invalid value for %s: %w", key, err)
+		}
+	}
+	return nil
+}
+
+// checkConfigKey validates settings that depend on more than one key, after
+// key has been set
+func checkConfigKey(key string) error {
+	if section, name, ok := findConfigSection(key); ok {
+		return section.check(name)
 	}
+	if strings.HasPrefix(key, "caption-") {
+		_, err := captionOptions()
+		return err
+	}
+	return nil
+}
+
+func findConfigKey(key string) (configKey, bool) {
+	for _, k := range configKeys {
+		if k.name == key {
+			return k, true
+		}
+	}
+	return configKey{}, false
+}
 
-	for _, validKey := range validKeys {
-		if key == validKey {
-			return nil
+// findConfigSection returns the section and preset name a <section>.<name>.<field>
+// key refers to, or false if key isn't a valid preset field
+func findConfigSection(key string) (configSection, string, bool) {
+	for _, section := range configSections {
+		rest, ok := strings.CutPrefix(key, section.key+".")
+		if !ok {
+			continue
+		}
+		name, field, ok := strings.Cut(rest, ".")
+		if !ok || name == "" {
+			return configSection{}, "", false
 		}
+		for _, f := range section.fields {
+			if field == f {
+				return section, name, true
+			}
+		}
+		return configSection{}, "", false
+	}
+	return configSection{}, "", false
+}
+
+func validateDuration(value string) error {
+	_, err := time.ParseDuration(value)
+	return err
+}
+
+func validateInt(value string) error {
+	if _, err := strconv.Atoi(value); err != nil {
+		return fmt.Errorf("%q is not a whole number", value)
+	}
+	return nil
+}
+
+func validateSceneThreshold(value string) error {
+	threshold, err := strconv.ParseFloat(value, 64)
+	if err != nil || threshold < 0 || threshold > 1 {
+		return fmt.Errorf("%q is not a number from 0 to 1", value)
 	}
+	return nil
+}
 
-	return fmt.Errorf("invalid configuration key: %s (valid keys: %v)", key, validKeys)
-}
\ No newline at end of file
+func validateFloat(value string) error {
+	if _, err := strconv.ParseFloat(value, 64); err != nil {
+		return fmt.Errorf("%q is not a number", value)
+	}
+	return nil
+}
@@ -0,0 +1,350 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"ai-video-editor/processing/ai"
+	"ai-video-editor/processing/store"
+	"ai-video-editor/processing/toolchain"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+type checkStatus string
+
+const (
+	statusPass checkStatus = "pass"
+	statusWarn checkStatus = "warn"
+	statusFail checkStatus = "fail"
+)
+
+// checkResult is the outcome of a single doctor check
+type checkResult struct {
+	Name   string      `json:"name"`
+	Status checkStatus `json:"status"`
+	Detail string      `json:"detail"`
+	Hint   string      `json:"hint,omitempty"`
+}
+
+var doctorJSON bool
+
+var doctorCmd = &cobra.Command{
+	Use:   "doctor",
+	Short: "Check that your environment is ready for processing",
+	Long: `Doctor verifies that ffmpeg and ffprobe are installed with the required codecs,
+that the temp and output directories are writable, that SQLite is usable, that
+your config file is valid and which transcription backends can be used.`,
+	Example: `  # Run all checks
+  ai-editor doctor
+
+  # Machine-readable output for CI
+  ai-editor doctor --json`,
+	RunE: runDoctor,
+}
+
+func init() {
+	rootCmd.AddCommand(doctorCmd)
+
+	doctorCmd.Flags().BoolVar(&doctorJSON, "json", false, "print results as JSON (same as --output-format json)")
+}
+
+func runDoctor(cmd *cobra.Command, args []string) error {
+	var results []checkResult
+
+	tc, toolResults := checkToolchain()
+	results = append(results, toolResults...)
+	results = append(results, checkCodecs(tc)...)
+	results = append(results, checkWritableDir("temp-dir", tempDir()))
+	results = append(results, checkWritableDir("output-dir", viper.GetString("output")))
+	results = append(results, checkSQLite())
+	results = append(results, checkConfigFile())
+	results = append(results, checkTranscriptionBackends()...)
+
+	failed := 0
+	for _, r := range results {
+		if r.Status == statusFail {
+			failed++
+		}
+	}
+
+	if doctorJSON && !structuredOutput() {
+		outputFormat = outputJSON
+	}
+	if structuredOutput() {
+		if err := writeStructured(struct {
+			Healthy bool          `json:"healthy"`
+			Checks  []checkResult `json:"checks"`
+		}{failed == 0, results}); err != nil {
+			return err
+		}
+	} else {
+		printCheckResults(results)
+	}
+
+	if failed > 0 {
+		cmd.SilenceUsage = true
+		return fmt.Errorf("%d check(s) failed", failed)
+	}
+	return nil
+}
+
+func printCheckResults(results []checkResult) {
+	icons := map[checkStatus]string{
+		statusPass: "✅",
+		statusWarn: "⚠️ ",
+		statusFail: "❌",
+	}
+
+	fmt.Println("🩺 AI Video Editor environment check")
+	fmt.Println()
+	for _, r := range results {
+		fmt.Printf("%s %-22s %s\n", icons[r.Status], r.Name, r.Detail)
+		if r.Hint != "" && r.Status != statusPass {
+			fmt.Printf("   💡 %s\n", r.Hint)
+		}
+	}
+	fmt.Println()
+}
+
+func checkToolchain() (*toolchain.Toolchain, []checkResult) {
+	tc, err := toolchain.Discover(toolchain.DefaultOptions())
+	if err != nil {
+		var de *toolchain.DiscoveryError
+		name := "ffmpeg"
+		if errors.As(err, &de) {
+			name = de.Binary
+		}
+		return nil, []checkResult{{
+			Name:   name,
+			Status: statusFail,
+			Detail: err.Error(),
+			Hint:   toolchain.InstallHint(name),
+		}}
+	}
+
+	var results []checkResult
+	for _, bin := range []toolchain.Binary{tc.FFmpeg, tc.FFprobe} {
+		results = append(results, checkResult{
+			Name:   bin.Name,
+			Status: statusPass,
+			Detail: fmt.Sprintf("%s (version %s, found via %s)", bin.Path, bin.Version, bin.Source),
+		})
+	}
+	return tc, results
+}
+
+func checkCodecs(tc *toolchain.Toolchain) []checkResult {
+	var results []checkResult
+	for _, enc := range toolchain.DefaultEncoders {
+		r := checkResult{Name: "encoder " + enc}
+		switch {
+		case tc == nil:
+			r.Status = statusFail
+			r.Detail = "skipped: ffmpeg not available"
+			r.Hint = toolchain.InstallHint("ffmpeg")
+		case tc.HasEncoder(enc):
+			r.Status = statusPass
+			r.Detail = "available"
+		default:
+			r.Status = statusFail
+			r.Detail = "not compiled into this ffmpeg build"
+			r.Hint = "install a full ffmpeg build (e.g. with --enable-gpl --enable-libx264)"
+		}
+		results = append(results, r)
+	}
+	return results
+}
+
+func checkWritableDir(name, dir string) checkResult {
+	r := checkResult{Name: name}
+	if dir == "" {
+		r.Status = statusWarn
+		r.Detail = "not configured"
+		r.Hint = fmt.Sprintf("set it with `ai-editor config set %s <dir>`", name)
+		return r
+	}
+
+	// Only look; the directory itself is created when a run needs it
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		abs = dir
+	}
+	existing, err := nearestExistingDir(abs)
+	if err != nil {
+		r.Status = statusFail
+		r.Detail = fmt.Sprintf("cannot use %s: %v", abs, err)
+		r.Hint = "choose a directory you have write access to"
+		return r
+	}
+
+	probe, err := os.CreateTemp(existing, ".doctor-*")
+	if err != nil {
+		r.Status = statusFail
+		r.Detail = fmt.Sprintf("%s is not writable: %v", existing, err)
+		r.Hint = "fix the directory permissions or choose another directory"
+		return r
+	}
+	probe.Close()
+	os.Remove(probe.Name())
+
+	r.Status = statusPass
+	if existing != abs {
+		r.Detail = fmt.Sprintf("%s will be created on first run (%s is writable)", abs, existing)
+		return r
+	}
+	r.Detail = abs + " is writable"
+	return r
+}
+
+// nearestExistingDir returns dir, or its closest ancestor when dir does not
+// exist yet. It fails if that path is not a directory.
+func nearestExistingDir(dir string) (string, error) {
+	for {
+		info, err := os.Stat(dir)
+		if err == nil {
+			if !info.IsDir() {
+				return "", fmt.Errorf("%s is not a directory", dir)
+			}
+			return dir, nil
+		}
+		if !errors.Is(err, fs.ErrNotExist) {
+			return "", err
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", err
+		}
+		dir = parent
+	}
+}
+
+func checkSQLite() checkResult {
+	r := checkResult{Name: "sqlite"}
+
+	// Only inspect the database; creating or migrating it is left to real runs
+	path := store.DefaultPath()
+	db, err := store.OpenReadOnly(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		r.Status = statusPass
+		r.Detail = fmt.Sprintf("%s does not exist yet, it is created on first run", path)
+		return r
+	}
+	if err != nil {
+		r.Status = statusFail
+		r.Detail = fmt.Sprintf("cannot open database: %v", err)
+		r.Hint = "rebuild with CGO_ENABLED=1 and a working C compiler, or set db-path to a writable location"
+		return r
+	}
+	defer db.Close()
+
+	version, err := db.SchemaVersion(context.Background())
+	if err != nil {
+		r.Status = statusFail
+		r.Detail = err.Error()
+		r.Hint = "move the database aside and let ai-editor create a new one"
+		return r
+	}
+
+	switch latest := store.SchemaLatest(); {
+	case version > latest:
+		r.Status = statusFail
+		r.Detail = fmt.Sprintf("%s has schema version %d, newer than this build supports (%d)", db.Path, version, latest)
+		r.Hint = "upgrade ai-editor"
+	case version < latest:
+		r.Status = statusPass
+		r.Detail = fmt.Sprintf("%s (schema version %d, upgraded to %d on next run)", db.Path, version, latest)
+	default:
+		r.Status = statusPass
+		r.Detail = fmt.Sprintf("%s (schema version %d)", db.Path, version)
+	}
+	return r
+}
+
+func checkConfigFile() checkResult {
+	r := checkResult{Name: "config"}
+
+	configFile := viper.ConfigFileUsed()
+	if configFile == "" {
+		r.Status = statusWarn
+		r.Detail = "no config file found, using defaults"
+		r.Hint = "create one with `ai-editor config set <key> <value>`"
+		return r
+	}
+
+	if _, err := os.Stat(configFile); os.IsNotExist(err) {
+		r.Status = statusWarn
+		r.Detail = fmt.Sprintf("%s does not exist, using defaults", configFile)
+		r.Hint = "create one with `ai-editor config set <key> <value>`"
+		return r
+	}
+
+	// Read the file on its own so flag bindings don't mask unknown keys
+	fileConfig := viper.New()
+	fileConfig.SetConfigFile(configFile)
+	if err := fileConfig.ReadInConfig(); err != nil {
+		r.Status = statusFail
+		r.Detail = fmt.Sprintf("%s is invalid: %v", configFile, err)
+		r.Hint = "fix the YAML syntax or run `ai-editor config reset`"
+		return r
+	}
+
+	var unknown []string
+	for _, key := range fileConfig.AllKeys() {
+		if validateConfigKey(key) != nil && processCmd.Flags().Lookup(key) == nil && rootCmd.PersistentFlags().Lookup(key) == nil {
+			unknown = append(unknown, key)
+		}
+	}
+	if len(unknown) > 0 {
+		r.Status = statusWarn
+		r.Detail = fmt.Sprintf("%s has unknown keys: %v", configFile, unknown)
+		r.Hint = "see `ai-editor config set --help` for valid keys"
+		return r
+	}
+
+	r.Status = statusPass
+	r.Detail = configFile + " is valid"
+	return r
+}
+
+func checkTranscriptionBackends() []checkResult {
+	cfg := ai.TranscriberConfigFromViper()
+
+	var results []checkResult
+	for _, b := range ai.CheckBackends(cfg) {
+		r := checkResult{Name: "backend " + b.Backend, Detail: b.Detail}
+		if b.Usable {
+			r.Status = statusPass
+		} else {
+			r.Status = statusWarn
+			r.Hint = "see `ai-editor config set --help` for transcription settings"
+		}
+		results = append(results, r)
+	}
+
+	// The backend actually selected by whisper-model must be constructible
+	selected := checkResult{Name: "whisper-model"}
+	if t, err := ai.NewTranscriber(cfg); err != nil {
+		selected.Status = statusFail
+		selected.Detail = err.Error()
+		selected.Hint = "set whisper-model to a usable backend, e.g. `ai-editor config set whisper-model local:base`"
+	} else {
+		selected.Status = statusPass
+		selected.Detail = "using " + t.Name()
+	}
+	results = append(results, selected)
+
+	return results
+}
+
+func tempDir() string {
+	if dir := viper.GetString("temp-dir"); dir != "" {
+		return dir
+	}
+	return "./temp"
+}
@@ -0,0 +1,498 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"ai-video-editor/processing/ai"
+	"ai-video-editor/processing/pipeline"
+	"ai-video-editor/processing/store"
+	"ai-video-editor/processing/workspace"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var (
+	jobsLimit     int
+	jobsStatus    string
+	jobsYes       bool
+	jobsFiles     bool
+	jobsForce     bool
+	retryKeepTemp bool
+)
+
+var jobsCmd = &cobra.Command{
+	Use:   "jobs",
+	Short: "Inspect and manage processing jobs",
+	Long: `Every process run is recorded in the job database with its prompt, source file,
+model settings, stage timings, outcome and generated clips. The jobs commands
+let you audit what was produced and why.`,
+}
+
+var jobsListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List recent jobs",
+	Example: `  # Show the 20 most recent jobs
+  ai-editor jobs list
+
+  # Show every failed job
+  ai-editor jobs list --status failed --limit 0`,
+	Args: cobra.NoArgs,
+	RunE: runJobsList,
+}
+
+var jobsShowCmd = &cobra.Command{
+	Use:     "show [job id]",
+	Short:   "Show a job's settings, stages and clips",
+	Args:    cobra.ExactArgs(1),
+	Example: `  ai-editor jobs show 20250808-142501-3f9a1c2b`,
+	RunE:    runJobsShow,
+}
+
+var jobsDeleteCmd = &cobra.Command{
+	Use:   "delete [job id]",
+	Short: "Delete a job's record",
+	Long: `Delete removes a job and its clip records from the job database. Generated clip
+files are left on disk unless --files is given, which deletes the job's clips,
+caption files, thumbnails and summary. Files another job also recorded are kept.
+
+A job recorded as running is refused; pass --force if its process was killed.`,
+	Args: cobra.ExactArgs(1),
+	Example: `  # Forget a job but keep its clips
+  ai-editor jobs delete 20250808-142501-3f9a1c2b
+
+  # Delete the job and its clip files without asking
+  ai-editor jobs delete 20250808-142501-3f9a1c2b --files --yes`,
+	RunE: runJobsDelete,
+}
+
+var jobsRetryCmd = &cobra.Command{
+	Use:   "retry [job id]",
+	Short: "Run a job again from the start with the same settings",
+	Long: `Retry starts a new job with the source, prompt, transcription model and options
+of an earlier one. Cached analysis is reused, so retrying is cheap when only the
+later stages need redoing. To continue a failed job instead, use resume.`,
+	Args:    cobra.ExactArgs(1),
+	Example: `  ai-editor jobs retry 20250808-142501-3f9a1c2b`,
+	RunE:    runJobsRetry,
+}
+
+func init() {
+	rootCmd.AddCommand(jobsCmd)
+	jobsCmd.AddCommand(jobsListCmd)
+	jobsCmd.AddCommand(jobsShowCmd)
+	jobsCmd.AddCommand(jobsDeleteCmd)
+	jobsCmd.AddCommand(jobsRetryCmd)
+
+	jobsListCmd.Flags().IntVarP(&jobsLimit, "limit", "n", 20, "maximum number of jobs to show (0 for all)")
+	jobsListCmd.Flags().StringVar(&jobsStatus, "status", "", "only show jobs with this status (pending, running, completed, failed, cancelled)")
+	jobsDeleteCmd.Flags().BoolVarP(&jobsYes, "yes", "y", false, "don't ask for confirmation")
+	jobsDeleteCmd.Flags().BoolVar(&jobsFiles, "files", false, "also delete the job's clip, caption, thumbnail and summary files")
+	jobsDeleteCmd.Flags().BoolVar(&jobsForce, "force", false, "delete a job recorded as running whose process is gone")
+	jobsRetryCmd.Flags().BoolVar(&retryKeepTemp, "keep-temp", false, "keep the job's temporary files for debugging")
+}
+
+func runJobsList(cmd *cobra.Command, args []string) error {
+	db, err := store.Open(store.DefaultPath())
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	// Filter before limiting so --status with --limit returns a full page
+	all, err := db.ListJobs(cmd.Context(), 0)
+	if err != nil {
+		return err
+	}
+	var jobs []*store.Job
+	for _, job := range all {
+		if jobsStatus != "" && job.Status != jobsStatus {
+			continue
+		}
+		jobs = append(jobs, job)
+		if jobsLimit > 0 && len(jobs) == jobsLimit {
+			break
+		}
+	}
+
+	if structuredOutput() {
+		infos := []jobInfo{}
+		for _, job := range jobs {
+			infos = append(infos, newJobInfo(job))
+		}
+		if outputFormat == outputNDJSON {
+			for _, info := range infos {
+				if err := writeStructured(info); err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+		return writeStructured(infos)
+	}
+
+	if len(jobs) == 0 {
+		fmt.Fprintln(humanOut(), "No jobs recorded yet.")
+		return nil
+	}
+
+	fmt.Fprintf(humanOut(), "%-26s %-10s %-16s %-6s %-24s %s\n", "JOB", "STATUS", "CREATED", "CLIPS", "SOURCE", "PROMPT")
+	for _, job := range jobs {
+		fmt.Fprintf(humanOut(), "%-26s %-10s %-16s %-6d %-24s %s\n",
+			job.Name,
+			job.Status,
+			job.CreatedAt.Local().Format("2006-01-02 15:04"),
+			job.ClipsGenerated,
+			truncate(job.SourceVideo, 24),
+			truncate(job.UserPrompt, 40),
+		)
+	}
+	return nil
+}
+
+func runJobsShow(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+
+	db, err := store.Open(store.DefaultPath())
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	job, err := findJob(ctx, db, args[0])
+	if err != nil {
+		return err
+	}
+	stages, err := db.ListStages(ctx, job.ID)
+	if err != nil {
+		return err
+	}
+	clips, err := db.ListClips(ctx, job.ID)
+	if err != nil {
+		return err
+	}
+
+	if structuredOutput() {
+		info := newJobInfo(job)
+		info.Stages = []stageTiming{}
+		for _, stage := range stages {
+			info.Stages = append(info.Stages, stageTiming{Name: stage.Name, ElapsedMS: stage.Duration.Milliseconds(), Error: stage.ErrorMessage})
+		}
+		info.Clips = []clipInfo{}
+		for _, clip := range clips {
+			info.Clips = append(info.Clips, clipInfo{
+				Index:         clip.Index,
+				Path:          clip.FilePath,
+				Start:         clip.Start.Seconds(),
+				End:           clip.End.Seconds(),
+				Duration:      clip.Duration().Seconds(),
+				Score:         clip.RelevanceScore,
+				Reason:        clip.Reason,
+				Text:          clip.TranscriptText,
+				CaptionPaths:  clip.CaptionPaths,
+				ThumbnailPath: clip.ThumbnailPath,
+			})
+		}
+		return writeStructured(info)
+	}
+
+	fmt.Fprintf(humanOut(), "📋 Job %s (#%d)\n", job.Name, job.ID)
+	fmt.Fprintf(humanOut(), "   Status: %s (%.0f%%)\n", job.Status, job.Progress*100)
+	if job.ErrorMessage != "" {
+		fmt.Fprintf(humanOut(), "   Error: %s\n", job.ErrorMessage)
+	}
+	fmt.Fprintf(humanOut(), "   Source: %s\n", job.SourceVideo)
+	fmt.Fprintf(humanOut(), "   Prompt: %s\n", job.UserPrompt)
+	fmt.Fprintf(humanOut(), "   Output: %s\n", job.OutputDirectory)
+	fmt.Fprintf(humanOut(), "   Created: %s\n", formatTime(job.CreatedAt))
+	fmt.Fprintf(humanOut(), "   Started: %s\n", formatTime(job.StartedAt))
+	fmt.Fprintf(humanOut(), "   Finished: %s\n", formatTime(job.CompletedAt))
+	if job.ProcessingTime > 0 {
+		fmt.Fprintf(humanOut(), "   Processing time: %s\n", job.ProcessingTime)
+	}
+
+	fmt.Fprintln(humanOut(), "\n🤖 Model:")
+	fmt.Fprintf(humanOut(), "   Transcription: %s\n", job.AIModel)
+	var opts pipeline.Options
+	if err := json.Unmarshal([]byte(job.ModelParameters), &opts); err == nil {
+		fmt.Fprintf(humanOut(), "   Clip length: %s\n", opts.Duration)
+		fmt.Fprintf(humanOut(), "   Max clips: %d\n", opts.MaxClips)
+		fmt.Fprintf(humanOut(), "   Quality: %s\n", opts.Quality)
+		if opts.Reframe.Enabled() {
+			fmt.Fprintf(humanOut(), "   Reframe: %s to %s\n", opts.Reframe.Mode, opts.Reframe.Aspect)
+		}
+		printEncodeSettings(opts.Encode)
+		if !opts.SkipAudio {
+			fmt.Fprintf(humanOut(), "   Audio chunks: %s with %s overlap\n", opts.Chunks.Length, opts.Chunks.Overlap)
+			if opts.Captions.MaxLines > 0 {
+				fmt.Fprintf(humanOut(), "   Captions: %d line(s) of %d characters, %g chars/sec\n",
+					opts.Captions.MaxLines, opts.Captions.MaxLineLength, opts.Captions.MaxCPS)
+			}
+			if opts.BurnCaptions {
+				fmt.Fprintf(humanOut(), "   Burned-in captions: %s %dpx, %s, %d words per line\n",
+					opts.CaptionStyle.Font, opts.CaptionStyle.Size, opts.CaptionStyle.Position, opts.CaptionStyle.MaxWordsPerLine)
+			}
+		}
+	} else if job.ModelParameters != "" {
+		fmt.Fprintf(humanOut(), "   Parameters: %s\n", job.ModelParameters)
+	}
+
+	if len(stages) > 0 {
+		fmt.Fprintln(humanOut(), "\n⏱️  Stages:")
+		for _, stage := range stages {
+			fmt.Fprintf(humanOut(), "   %d. %-42s %-10s %s\n", stage.Index+1, stage.Name, stage.Status, stage.Duration)
+			if stage.ErrorMessage != "" {
+				fmt.Fprintf(humanOut(), "      ❌ %s\n", stage.ErrorMessage)
+			}
+		}
+	}
+
+	fmt.Fprintf(humanOut(), "\n🎞️  Clips (%d of %d planned):\n", len(clips), job.TotalClipsPlanned)
+	for _, clip := range clips {
+		fmt.Fprintf(humanOut(), "   %2d. %s (%s - %s, score %.2f)\n", clip.Index, clip.FilePath,
+			formatTimestamp(clip.Start), formatTimestamp(clip.End), clip.RelevanceScore)
+		if clip.Reason != "" {
+			fmt.Fprintf(humanOut(), "       Reason: %s\n", clip.Reason)
+		}
+		if clip.TranscriptText != "" {
+			fmt.Fprintf(humanOut(), "       Text: %s\n", truncate(clip.TranscriptText, 100))
+		}
+		if len(clip.CaptionPaths) > 0 {
+			fmt.Fprintf(humanOut(), "       Captions: %s\n", strings.Join(clip.CaptionPaths, ", "))
+		}
+		if clip.ThumbnailPath != "" {
+			fmt.Fprintf(humanOut(), "       Thumbnail: %s\n", clip.ThumbnailPath)
+		}
+	}
+	return nil
+}
+
+func runJobsDelete(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+
+	db, err := store.Open(store.DefaultPath())
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	job, err := findJob(ctx, db, args[0])
+	if err != nil {
+		return err
+	}
+	if job.Status == store.StatusRunning && !jobsForce {
+		return fmt.Errorf("job %s is still running (use --force if its process was killed)", job.Name)
+	}
+
+	var files []string
+	if jobsFiles {
+		if files, err = jobFiles(ctx, db, job); err != nil {
+			return err
+		}
+	}
+
+	if !jobsYes {
+		what := "the record of"
+		if jobsFiles {
+			what = fmt.Sprintf("%d file(s) and the record of", len(files))
+		}
+		fmt.Fprintf(humanOut(), "⚠️  This will delete %s job %s. Continue? (y/N): ", what, job.Name)
+
+		var response string
+		fmt.Scanln(&response)
+		if response != "y" && response != "Y" {
+			fmt.Fprintln(humanOut(), "Delete cancelled.")
+			return nil
+		}
+	}
+
+	for _, path := range files {
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to delete job file: %w", err)
+		}
+	}
+	// Runs write into a directory named after the job; remove it once empty.
+	// Older jobs wrote straight into the shared output directory, which stays.
+	if jobsFiles && filepath.Base(job.OutputDirectory) == job.Name {
+		os.Remove(job.OutputDirectory)
+	}
+
+	if err := db.DeleteJob(ctx, job.ID); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(humanOut(), "✅ Deleted job %s\n", job.Name)
+	return nil
+}
+
+// jobFiles lists the files a job wrote that no other job recorded: its clips
+// with their captions and thumbnails, and its run summary
+func jobFiles(ctx context.Context, db *store.Store, job *store.Job) ([]string, error) {
+	clips, err := db.ListClips(ctx, job.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	var files []string
+	for _, clip := range clips {
+		for _, path := range clip.Files() {
+			inUse, err := db.FileInUse(ctx, path, job.ID)
+			if err != nil {
+				return nil, err
+			}
+			if inUse {
+				fmt.Fprintf(os.Stderr, "⚠️  keeping %s, another job recorded it too\n", path)
+				continue
+			}
+			files = append(files, path)
+		}
+	}
+
+	// Older jobs shared an output directory and summary name, so only delete
+	// a summary this job wrote
+	summary := filepath.Join(job.OutputDirectory, pipeline.SummaryName(job.SourceVideo))
+	if data, err := os.ReadFile(summary); err == nil {
+		var owner struct {
+			JobID string `json:"job_id"`
+		}
+		if json.Unmarshal(data, &owner) == nil && owner.JobID == job.Name {
+			files = append(files, summary)
+		}
+	}
+	return files, nil
+}
+
+func runJobsRetry(cmd *cobra.Command, args []string) error {
+	db, err := store.Open(store.DefaultPath())
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	original, err := findJob(cmd.Context(), db, args[0])
+	if err != nil {
+		return err
+	}
+
+	var opts pipeline.Options
+	if err := json.Unmarshal([]byte(original.ModelParameters), &opts); err != nil {
+		return fmt.Errorf("failed to decode options of job %s: %w", original.Name, err)
+	}
+	if _, err := os.Stat(original.SourceVideo); err != nil {
+		return fmt.Errorf("source video of job %s is no longer available: %w", original.Name, err)
+	}
+	if err := os.MkdirAll(opts.OutputDir, 0755); err != nil {
+		return fmt.Errorf("failed to create output directory: %w", err)
+	}
+
+	// Cancel any running ffmpeg process on Ctrl-C or SIGTERM
+	ctx, stop := interruptContext(cmd.Context())
+	defer stop()
+
+	ws, err := workspace.New(tempDir(), retryKeepTemp)
+	if err != nil {
+		return err
+	}
+	defer cleanupWorkspace(ws)
+
+	job := &pipeline.Job{
+		ID:        ws.ID,
+		Input:     original.SourceVideo,
+		Prompt:    original.UserPrompt,
+		Options:   opts,
+		Workspace: ws,
+		Cache:     db,
+	}
+
+	record := newJobRecord(job, original.AIModel)
+	if err := db.CreateJob(ctx, record); err != nil {
+		return err
+	}
+
+	// Transcribe with the model the original job used
+	cfg := ai.TranscriberConfigFromViper()
+	cfg.Model = original.AIModel
+	if err := prepareJob(job, cfg); err != nil {
+		return failJob(db, record, job, err)
+	}
+
+	if !viper.GetBool("quiet") {
+		fmt.Fprintf(humanOut(), "🔁 Retrying job %s as %s\n", original.Name, record.Name)
+		fmt.Fprintf(humanOut(), "Input: %s\n", job.Input)
+		fmt.Fprintf(humanOut(), "Prompt: %s\n", job.Prompt)
+		fmt.Fprintln(humanOut())
+	}
+
+	return executeJob(ctx, db, record, job, pipeline.NewRunner(nil), 0)
+}
+
+// jobInfo is a job record as reported by --output-format json and ndjson
+type jobInfo struct {
+	JobID          string          `json:"job_id"`
+	Status         string          `json:"status"`
+	Input          string          `json:"input"`
+	Prompt         string          `json:"prompt"`
+	OutputDir      string          `json:"output_dir"`
+	Model          string          `json:"model"`
+	Options        json.RawMessage `json:"options,omitempty"`
+	Progress       float64         `json:"progress"`
+	ClipsGenerated int             `json:"clips_generated"`
+	ClipsPlanned   int             `json:"clips_planned"`
+	CreatedAt      time.Time       `json:"created_at"`
+	StartedAt      *time.Time      `json:"started_at,omitempty"`
+	CompletedAt    *time.Time      `json:"completed_at,omitempty"`
+	ProcessingMS   int64           `json:"processing_ms,omitempty"`
+	Error          string          `json:"error,omitempty"`
+	Stages         []stageTiming   `json:"stages,omitempty"`
+	Clips          []clipInfo      `json:"clips,omitempty"`
+}
+
+func newJobInfo(job *store.Job) jobInfo {
+	info := jobInfo{
+		JobID:          job.Name,
+		Status:         job.Status,
+		Input:          job.SourceVideo,
+		Prompt:         job.UserPrompt,
+		OutputDir:      job.OutputDirectory,
+		Model:          job.AIModel,
+		Progress:       job.Progress,
+		ClipsGenerated: job.ClipsGenerated,
+		ClipsPlanned:   job.TotalClipsPlanned,
+		CreatedAt:      job.CreatedAt,
+		ProcessingMS:   job.ProcessingTime.Milliseconds(),
+		Error:          job.ErrorMessage,
+	}
+	if json.Valid([]byte(job.ModelParameters)) {
+		info.Options = json.RawMessage(job.ModelParameters)
+	}
+	if !job.StartedAt.IsZero() {
+		info.StartedAt = &job.StartedAt
+	}
+	if !job.CompletedAt.IsZero() {
+		info.CompletedAt = &job.CompletedAt
+	}
+	return info
+}
+
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return "-"
+	}
+	return t.Local().Format("2006-01-02 15:04:05")
+}
+
+// truncate shortens s to at most n runes, marking the cut with an ellipsis
+func truncate(s string, n int) string {
+	s = strings.Join(strings.Fields(s), " ")
+	runes := []rune(s)
+	if len(runes) <= n {
+		return s
+	}
+	return string(runes[:n-1]) + "…"
+}
@@ -0,0 +1,287 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"ai-video-editor/processing/pipeline"
+	"ai-video-editor/processing/store"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// Values of --output-format
+const (
+	outputText   = "text"
+	outputJSON   = "json"
+	outputNDJSON = "ndjson"
+)
+
+var outputFormat string
+
+// validateOutputFormat runs before every command, after the config file is
+// loaded, so output-format can also be set there
+func validateOutputFormat(cmd *cobra.Command, args []string) error {
+	outputFormat = viper.GetString("output-format")
+	switch outputFormat {
+	case outputText, outputJSON, outputNDJSON:
+		return nil
+	case "":
+		outputFormat = outputText
+		return nil
+	default:
+		return fmt.Errorf("invalid output format %q (use text, json or ndjson)", outputFormat)
+	}
+}
+
+// structuredOutput reports whether stdout carries JSON rather than messages
+func structuredOutput() bool {
+	return outputFormat == outputJSON || outputFormat == outputNDJSON
+}
+
+// humanOut is where decorated messages go: stdout, or stderr when stdout
+// carries structured output
+func humanOut() *os.File {
+	if structuredOutput() {
+		return os.Stderr
+	}
+	return os.Stdout
+}
+
+// writeStructured prints v to stdout as indented JSON, or as a single line for ndjson
+func writeStructured(v any) error {
+	enc := json.NewEncoder(os.Stdout)
+	if outputFormat == outputJSON {
+		enc.SetIndent("", "  ")
+	}
+	if err := enc.Encode(v); err != nil {
+		return fmt.Errorf("failed to encode output: %w", err)
+	}
+	return nil
+}
+
+// summaryWritten is set once a command has written its final structured
+// output, so a failure isn't reported twice
+var summaryWritten bool
+
+// commandError is how a failed command that doesn't run a job reports its
+// error to automation
+type commandError struct {
+	Type    string    `json:"type"`
+	Time    time.Time `json:"time"`
+	Command string    `json:"command"`
+	Error   string    `json:"error"`
+}
+
+// reportFailure writes the error that ended a command to stdout when output
+// is structured and the command hadn't reported it itself, so automation
+// always gets a final object: a failed run summary for the commands that run
+// jobs, or an error for the rest
+func reportFailure(cmd *cobra.Command, err error) {
+	// Flag and argument errors stop cobra before validateOutputFormat runs
+	if outputFormat == "" && validateOutputFormat(cmd, nil) != nil {
+		return
+	}
+	if !structuredOutput() || summaryWritten {
+		return
+	}
+
+	now := time.Now().UTC()
+	args := cmd.Flags().Args()
+	switch cmd {
+	case processCmd, resumeCmd, jobsRetryCmd:
+		summary := jobSummary{
+			Type:   "summary",
+			Time:   now,
+			Status: store.StatusFailed,
+			Clips:  []clipInfo{},
+			Stages: []stageTiming{},
+			Error:  err.Error(),
+		}
+		if errors.Is(err, context.Canceled) {
+			summary.Status = store.StatusCancelled
+		}
+		if cmd == processCmd && len(args) == 2 {
+			summary.Input, summary.Prompt = args[0], args[1]
+		}
+		if cmd == resumeCmd && len(args) == 1 {
+			summary.JobID = args[0]
+		}
+		writeStructured(summary)
+	default:
+		writeStructured(commandError{Type: "error", Time: now, Command: cmd.CommandPath(), Error: err.Error()})
+	}
+}
+
+// clipInfo is a generated clip as reported to automation, with times in seconds
+type clipInfo struct {
+	Index         int      `json:"index"`
+	Path          string   `json:"path"`
+	Start         float64  `json:"start"`
+	End           float64  `json:"end"`
+	Duration      float64  `json:"duration"`
+	Score         float64  `json:"score"`
+	Reason        string   `json:"reason,omitempty"`
+	Text          string   `json:"text,omitempty"`
+	CaptionPaths  []string `json:"caption_paths,omitempty"`
+	ThumbnailPath string   `json:"thumbnail_path,omitempty"`
+}
+
+func newClipInfo(c pipeline.Clip) clipInfo {
+	return clipInfo{
+		Index:         c.Index,
+		Path:          c.Path,
+		Start:         c.Start.Seconds(),
+		End:           c.End.Seconds(),
+		Duration:      c.Duration().Seconds(),
+		Score:         c.Score,
+		Reason:        c.Reason,
+		Text:          c.Text,
+		CaptionPaths:  c.CaptionPaths,
+		ThumbnailPath: c.ThumbnailPath,
+	}
+}
+
+// stageTiming is a stage's outcome in the final summary
+type stageTiming struct {
+	Name      string `json:"name"`
+	ElapsedMS int64  `json:"elapsed_ms"`
+	Error     string `json:"error,omitempty"`
+}
+
+// jobSummary is the final result of a run: the last ndjson event, or the
+// whole output for json
+type jobSummary struct {
+	Type      string        `json:"type"`
+	Time      time.Time     `json:"time"`
+	JobID     string        `json:"job_id"`
+	Status    string        `json:"status"`
+	Input     string        `json:"input"`
+	Prompt    string        `json:"prompt"`
+	OutputDir string        `json:"output_dir"`
+	Clips     []clipInfo    `json:"clips"`
+	Stages    []stageTiming `json:"stages"`
+	Warnings  []string      `json:"warnings,omitempty"`
+	Error     string        `json:"error,omitempty"`
+}
+
+// event is one line of ndjson output
+type event struct {
+	Type      string    `json:"type"`
+	Time      time.Time `json:"time"`
+	JobID     string    `json:"job_id"`
+	Stage     string    `json:"stage,omitempty"`
+	Step      int       `json:"step,omitempty"`
+	Steps     int       `json:"steps,omitempty"`
+	ElapsedMS int64     `json:"elapsed_ms,omitempty"`
+	Percent   *float64  `json:"percent,omitempty"`
+	Clip      *clipInfo `json:"clip,omitempty"`
+	Message   string    `json:"message,omitempty"`
+	Error     string    `json:"error,omitempty"`
+}
+
+// eventObserver reports a run as structured events: each one as it happens
+// for ndjson, or only the final summary for json
+type eventObserver struct {
+	mu          sync.Mutex
+	jobID       string
+	lastPercent int
+	stages      []stageTiming
+	warnings    []string
+}
+
+func newEventObserver(jobID string) *eventObserver {
+	return &eventObserver{jobID: jobID, lastPercent: -1}
+}
+
+func (o *eventObserver) emit(e event) {
+	if outputFormat != outputNDJSON {
+		return
+	}
+	e.Time = time.Now().UTC()
+	e.JobID = o.jobID
+	writeStructured(e)
+}
+
+func (o *eventObserver) StageStarted(job *pipeline.Job, index, total int, stage pipeline.Stage) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	o.lastPercent = -1
+	o.emit(event{Type: "stage_started", Stage: stage.Name(), Step: index + 1, Steps: total})
+}
+
+func (o *eventObserver) StageProgress(job *pipeline.Job, stage pipeline.Stage, done, total float64) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	// One event per whole percent keeps the stream readable
+	percent := int(done / total * 100)
+	if percent == o.lastPercent {
+		return
+	}
+	o.lastPercent = percent
+	p := float64(percent)
+	o.emit(event{Type: "progress", Stage: stage.Name(), Percent: &p})
+}
+
+func (o *eventObserver) StageFinished(job *pipeline.Job, stage pipeline.Stage, elapsed time.Duration, err error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	timing := stageTiming{Name: stage.Name(), ElapsedMS: elapsed.Milliseconds()}
+	if err != nil {
+		timing.Error = err.Error()
+	}
+	o.stages = append(o.stages, timing)
+	o.emit(event{Type: "stage_finished", Stage: stage.Name(), ElapsedMS: timing.ElapsedMS, Error: timing.Error})
+
+	if _, ok := stage.(*pipeline.ExtractionStage); ok {
+		for _, clip := range job.Clips {
+			info := newClipInfo(clip)
+			o.emit(event{Type: "clip", Clip: &info})
+		}
+	}
+}
+
+// warn records a warning for the summary
+func (o *eventObserver) warn(message string) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	o.warnings = append(o.warnings, message)
+	o.emit(event{Type: "warning", Message: message})
+}
+
+// finish writes the summary of the run
+func (o *eventObserver) finish(job *pipeline.Job, status string, runErr error) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	summary := jobSummary{
+		Type:      "summary",
+		Time:      time.Now().UTC(),
+		JobID:     o.jobID,
+		Status:    status,
+		Input:     job.Input,
+		Prompt:    job.Prompt,
+		OutputDir: job.OutputDir(),
+		Clips:     []clipInfo{},
+		Stages:    o.stages,
+		Warnings:  o.warnings,
+	}
+	for _, clip := range job.Clips {
+		summary.Clips = append(summary.Clips, newClipInfo(clip))
+	}
+	if runErr != nil {
+		summary.Error = runErr.Error()
+	}
+	summaryWritten = true
+	return writeStructured(summary)
+}
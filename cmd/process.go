@@ -1,22 +1,49 @@
 package cmd
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
+	"os/signal"
 	"path/filepath"
 	"strings"
+	"syscall"
+	"time"
+
+	"ai-video-editor/processing/ai"
+	"ai-video-editor/processing/analysis"
+	"ai-video-editor/processing/captions"
+	"ai-video-editor/processing/pipeline"
+	"ai-video-editor/processing/store"
+	"ai-video-editor/processing/toolchain"
 	"ai-video-editor/processing/video"
+	"ai-video-editor/processing/workspace"
 
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 )
 
 var (
-	outputDir    string
-	clipDuration string
-	maxClips     int
-	quality      string
-	skipAudio    bool
+	outputDir        string
+	clipDuration     string
+	maxClips         int
+	quality          string
+	skipAudio        bool
+	keepTemp         bool
+	noCache          bool
+	burnCaptions     bool
+	captionStyleName string
+	reframeMode      string
+	aspectRatio      string
+
+	// Resolved from --quality and --duration by validateProcessFlags
+	encodeSettings  video.EncodeSettings
+	clipLength      analysis.DurationRange
+	captionSettings captions.Options
+	captionStyle    captions.Style
+	reframe         video.ReframeOptions
 )
 
 var processCmd = &cobra.Command{
@@ -29,23 +56,40 @@ and generates captioned clips ready for social media or other uses.`,
 	Example: `  # Extract funny moments as 30-second clips
   ai-editor process video.mp4 "find funny moments" --duration 30s
   
+  # Let clips run anywhere from 15 seconds to a minute
+  ai-editor process podcast.mp4 "hot takes" --duration 15s-60s
+
   # Get educational highlights with high quality
   ai-editor process lecture.mp4 "key learning points" --quality high --max-clips 5
   
+  # Vertical clips for short-form platforms, following the speaker
+  ai-editor process interview.mp4 "strongest answers" --reframe track
+
+  # Burn word-by-word highlighted captions into the clips
+  ai-editor process talk.mp4 "best moments" --caption-style lower-third
+
   # Process to specific output directory
   ai-editor process presentation.mp4 "important quotes" --output ./clips`,
-	RunE: runProcess,
+	PreRunE: validateProcessFlags,
+	RunE:    runProcess,
 }
 
 func init() {
 	rootCmd.AddCommand(processCmd)
 
 	// Command-specific flags
-	processCmd.Flags().StringVarP(&outputDir, "output", "o", "./clips", "output directory for generated clips")
-	processCmd.Flags().StringVarP(&clipDuration, "duration", "d", "30s", "target duration for clips (e.g., 15s, 1m)")
+	processCmd.Flags().StringVarP(&outputDir, "output", "o", "./clips", "output directory; each run writes its clips to a subdirectory named after the job")
+	processCmd.Flags().StringVarP(&clipDuration, "duration", "d", "30s", "clip length: exact (30s), a range (15s-60s) or approximate (~45s)")
 	processCmd.Flags().IntVarP(&maxClips, "max-clips", "m", 10, "maximum number of clips to generate")
-	processCmd.Flags().StringVarP(&quality, "quality", "", "medium", "output quality (low, medium, high)")
+	processCmd.Flags().StringVarP(&quality, "quality", "", "medium", "output quality (low, medium, high or a preset from quality-presets in the config file)")
 	processCmd.Flags().BoolVar(&skipAudio, "skip-audio", false, "skip audio processing and use video only")
+	processCmd.Flags().BoolVar(&keepTemp, "keep-temp", false, "keep the job's temporary files for debugging")
+	processCmd.Flags().BoolVar(&noCache, "no-cache", false, "ignore cached analysis of this video and re-transcribe")
+
+	processCmd.Flags().BoolVar(&burnCaptions, "burn-captions", false, "burn karaoke-style captions into the clips (re-encodes every clip)")
+	processCmd.Flags().StringVar(&reframeMode, "reframe", "", "fit clips to --aspect: center (crop the middle), blur-pad (letterbox over a blurred fill) or track (crop following the action)")
+	processCmd.Flags().StringVar(&aspectRatio, "aspect", "9:16", "target aspect ratio for --reframe, as W:H")
+	processCmd.Flags().StringVar(&captionStyleName, "caption-style", captions.DefaultStyleName, "style of burned-in captions (bold-center, lower-third, minimal or a style from caption-styles in the config file); implies --burn-captions")
 
 	// Bind flags to viper for config file support
 	viper.BindPFlag("output", processCmd.Flags().Lookup("output"))
@@ -54,6 +98,71 @@ func init() {
 	viper.BindPFlag("quality", processCmd.Flags().Lookup("quality"))
 }
 
+// validateProcessFlags rejects bad flag values before any work starts. It runs
+// after the config file is loaded so presets defined there are accepted.
+func validateProcessFlags(cmd *cobra.Command, args []string) error {
+	if !cmd.Flags().Changed("duration") {
+		if d := viper.GetString("default-duration"); d != "" {
+			clipDuration = d
+		} else {
+			clipDuration = viper.GetString("duration")
+		}
+	}
+
+	length, err := analysis.ParseDurationSpec(clipDuration)
+	if err != nil {
+		return err
+	}
+	clipLength = length
+
+	if !cmd.Flags().Changed("quality") {
+		if q := viper.GetString("default-quality"); q != "" {
+			quality = q
+		} else {
+			quality = viper.GetString("quality")
+		}
+	}
+
+	settings, err := video.QualityPreset(quality)
+	if err != nil {
+		return err
+	}
+	encodeSettings = settings
+
+	captionSettings, err = captionOptions()
+	if err != nil {
+		return err
+	}
+
+	if cmd.Flags().Changed("caption-style") {
+		burnCaptions = true
+	} else if name := viper.GetString("default-caption-style"); name != "" {
+		captionStyleName = name
+	}
+	if burnCaptions && skipAudio {
+		return fmt.Errorf("--burn-captions needs audio; drop --skip-audio")
+	}
+
+	captionStyle, err = captions.StylePreset(captionStyleName)
+	if err != nil {
+		return err
+	}
+
+	mode, err := video.ParseReframeMode(reframeMode)
+	if err != nil {
+		return err
+	}
+	aspect, err := video.ParseAspect(aspectRatio)
+	if err != nil {
+		return err
+	}
+	if cmd.Flags().Changed("aspect") && mode == video.ReframeNone {
+		return fmt.Errorf("--aspect needs --reframe center, blur-pad or track")
+	}
+	reframe = video.ReframeOptions{Mode: mode, Aspect: aspect}
+	return nil
+}
+
 func runProcess(cmd *cobra.Command, args []string) error {
 	videoFile := args[0]
 	prompt := args[1]
@@ -70,55 +179,350 @@ func runProcess(cmd *cobra.Command, args []string) error {
 
 	// Display processing info
 	if !viper.GetBool("quiet") {
-		fmt.Printf("🎬 AI Video Editor\n")
-		fmt.Printf("Input: %s\n", videoFile)
-		fmt.Printf("Prompt: %s\n", prompt)
-		fmt.Printf("Output: %s\n", outputDir)
-		fmt.Printf("Duration: %s\n", clipDuration)
-		fmt.Printf("Max clips: %d\n", maxClips)
-		fmt.Printf("Quality: %s\n", quality)
-		fmt.Println()
-	}
-
-	// TODO: Implement the actual processing pipeline
-	fmt.Println("🔄 Starting video processing...")
-
-	// Placeholder for processing steps
-	// steps := []string{
-	// 	"Analyzing video metadata",
-	// 	"Extracting audio track",
-	// 	"Performing speech-to-text transcription",
-	// 	"Running AI content analysis",
-	// 	"Identifying clip segments",
-	// 	"Extracting video clips",
-	// 	"Generating captions",
-	// 	"Finalizing output files",
-	// }
-
-	// for i, step := range steps {
-	// 	if !viper.GetBool("quiet") {
-	// 		fmt.Printf("📋 Step %d/%d: %s...\n", i+1, len(steps), step)
-	// 	}
-
-	// 	// Simulate processing time
-	// 	time.Sleep(500 * time.Millisecond)
-
-	// 	if viper.GetBool("verbose") {
-	// 		fmt.Printf("   ✅ %s completed\n", step)
-	// 	}
-
-	// 	if i == 0 {
-	video.Analyze(videoFile)
-	// 	}
-	// }
-
-	// if !viper.GetBool("quiet") {
-	// 	fmt.Printf("\n🎉 Processing complete! Generated clips saved to: %s\n", outputDir)
-	// }
+		fmt.Fprintf(humanOut(), "🎬 AI Video Editor\n")
+		fmt.Fprintf(humanOut(), "Input: %s\n", videoFile)
+		fmt.Fprintf(humanOut(), "Prompt: %s\n", prompt)
+		fmt.Fprintf(humanOut(), "Output: %s\n", outputDir)
+		fmt.Fprintf(humanOut(), "Duration: %s\n", clipLength)
+		fmt.Fprintf(humanOut(), "Max clips: %d\n", maxClips)
+		fmt.Fprintf(humanOut(), "Quality: %s\n", quality)
+		if reframe.Enabled() {
+			fmt.Fprintf(humanOut(), "Reframe: %s to %s\n", reframe.Mode, reframe.Aspect)
+		}
+		if viper.GetBool("verbose") {
+			printEncodeSettings(encodeSettings)
+		}
+		fmt.Fprintln(humanOut())
+	}
+
+	// Cancel any running ffmpeg process on Ctrl-C or SIGTERM
+	ctx, stop := interruptContext(cmd.Context())
+	defer stop()
+
+	// Every temporary file for this run lives in its own workspace, removed on
+	// success, failure or interruption unless --keep-temp is set
+	ws, err := workspace.New(tempDir(), keepTemp)
+	if err != nil {
+		return err
+	}
+	defer cleanupWorkspace(ws)
+
+	// Every run leaves a record in the job database, whatever its outcome, so
+	// the job is recorded before anything else can fail. The same database
+	// caches analysis results between runs.
+	db, err := store.Open(store.DefaultPath())
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	model := transcriptionModel()
+	job := &pipeline.Job{
+		ID:     ws.ID,
+		Input:  videoFile,
+		Prompt: prompt,
+		Options: pipeline.Options{
+			OutputDir:      outputDir,
+			Duration:       clipLength,
+			MaxClips:       maxClips,
+			Quality:        quality,
+			Encode:         encodeSettings,
+			SkipAudio:      skipAudio,
+			Chunks:         chunkOptions(),
+			Captions:       captionSettings,
+			BurnCaptions:   burnCaptions,
+			CaptionStyle:   captionStyle,
+			Reframe:        reframe,
+			SceneThreshold: sceneThreshold(),
+			CacheKey:       pipeline.CacheKey(model, viper.GetString("language"), chunkOptions(), sceneThreshold()),
+			NoCache:        noCache,
+		},
+		Workspace: ws,
+		Cache:     db,
+	}
+
+	record := newJobRecord(job, model)
+	if err := db.CreateJob(ctx, record); err != nil {
+		return err
+	}
+	if err := prepareJob(job, ai.TranscriberConfigFromViper()); err != nil {
+		return failJob(db, record, job, err)
+	}
+
+	return executeJob(ctx, db, record, job, pipeline.NewRunner(nil), 0)
+}
+
+// prepareJob resolves the ffmpeg toolchain and transcriber a job needs and
+// checks that ffmpeg supports everything its options ask for
+func prepareJob(job *pipeline.Job, cfg ai.TranscriberConfig) error {
+	// Resolve ffmpeg/ffprobe from config, env, $PATH or common install locations
+	tc, err := toolchain.Discover(toolchain.DefaultOptions())
+	if err != nil {
+		return err
+	}
+	if err := preflight(tc, job.Options); err != nil {
+		return err
+	}
+	job.Toolchain = tc
+
+	// Transcription is only needed when there's audio to transcribe
+	if !job.Options.SkipAudio {
+		job.Transcriber, err = ai.NewTranscriber(cfg)
+		if err != nil {
+			return fmt.Errorf("transcription unavailable (use --skip-audio to process video only): %w", err)
+		}
+	}
+	return nil
+}
+
+// preflight checks that ffmpeg has the filters a job's options need, so a run
+// fails before any work rather than at its last stages
+func preflight(tc *toolchain.Toolchain, opts pipeline.Options) error {
+	if opts.BurnCaptions {
+		if err := tc.Verify(nil, []string{"ass"}); err != nil {
+			return fmt.Errorf("cannot burn in captions: %w (install an ffmpeg built with --enable-libass)", err)
+		}
+	}
+	if err := tc.Verify(nil, reframeFilters(opts.Reframe.Mode)); err != nil {
+		return fmt.Errorf("cannot reframe clips: %w", err)
+	}
+	if opts.SceneThreshold > 0 {
+		if err := tc.Verify(nil, sceneFilters); err != nil {
+			return fmt.Errorf("cannot detect scene changes: %w (set scene-threshold to 0 to skip)", err)
+		}
+	}
+	return nil
+}
+
+// failJob records an error hit before the pipeline started as the outcome of
+// the job, so the run still shows up in `jobs list`, and reports it as the
+// run's summary when output is structured
+func failJob(db *store.Store, record *store.Job, job *pipeline.Job, err error) error {
+	if ferr := db.FinishJob(context.Background(), record.ID, err); ferr != nil {
+		fmt.Fprintf(os.Stderr, "⚠️  failed to record job outcome: %v\n", ferr)
+	}
+	if structuredOutput() {
+		newEventObserver(record.Name).finish(job, store.StatusFailed, err)
+	}
+	return err
+}
+
+// executeJob runs a recorded job from the given stage, keeping its database
+// record up to date, and reports the outcome
+func executeJob(ctx context.Context, db *store.Store, record *store.Job, job *pipeline.Job, runner *pipeline.Runner, start int) error {
+	if err := db.StartJob(ctx, record.ID); err != nil {
+		return err
+	}
+
+	if !viper.GetBool("quiet") {
+		fmt.Fprintln(humanOut(), "🔄 Starting video processing...")
+	}
+
+	recorder := &pipeline.Recorder{Store: db, JobID: record.ID}
+	events := newEventObserver(record.Name)
+	observers := pipeline.Observers{newConsoleObserver(), recorder}
+	if structuredOutput() {
+		observers = append(observers, events)
+	}
+	runner.Observer = observers
+	runErr := runner.RunFrom(ctx, job, start)
+
+	// ctx is cancelled on Ctrl-C or SIGTERM, but the outcome must still be written
+	if err := db.FinishJob(context.Background(), record.ID, runErr); err != nil {
+		warn(events, "failed to record job outcome: %v", err)
+	}
+	if err := recorder.Err(); err != nil {
+		warn(events, "failed to record job progress: %v", err)
+	}
+	if structuredOutput() {
+		status := store.StatusCompleted
+		if runErr != nil {
+			status = store.StatusFailed
+			if errors.Is(runErr, context.Canceled) {
+				status = store.StatusCancelled
+			}
+		}
+		if err := events.finish(job, status, runErr); err != nil {
+			return err
+		}
+	}
+	if runErr != nil {
+		fmt.Fprintf(os.Stderr, "💡 Pick up where this run stopped with: ai-editor resume %s\n", record.Name)
+		return runErr
+	}
+
+	if !viper.GetBool("quiet") {
+		fmt.Fprintf(humanOut(), "\n🎉 Processing complete! Generated %d clip(s) saved to: %s\n", len(job.Clips), job.OutputDir())
+		for _, clip := range job.Clips {
+			fmt.Fprintf(humanOut(), "   🎞️  %s (%s - %s)\n", clip.Path, formatTimestamp(clip.Start), formatTimestamp(clip.End))
+		}
+	}
 
 	return nil
 }
 
+// interruptContext is cancelled on Ctrl-C or a SIGTERM from a supervisor, so
+// the run stops its ffmpeg processes, cleans up its workspace and is recorded
+// as cancelled instead of being killed outright
+func interruptContext(parent context.Context) (context.Context, context.CancelFunc) {
+	return signal.NotifyContext(parent, os.Interrupt, syscall.SIGTERM)
+}
+
+// warn prints a warning to stderr and adds it to the structured output
+func warn(events *eventObserver, format string, args ...any) {
+	message := fmt.Sprintf(format, args...)
+	fmt.Fprintf(os.Stderr, "⚠️  %s\n", message)
+	if structuredOutput() {
+		events.warn(message)
+	}
+}
+
+// cleanupWorkspace removes a job's workspace, or reports where it was kept
+func cleanupWorkspace(ws *workspace.Workspace) {
+	if err := ws.Cleanup(); err != nil {
+		fmt.Fprintf(os.Stderr, "⚠️  %v\n", err)
+	} else if ws.Keep && !viper.GetBool("quiet") {
+		fmt.Fprintf(humanOut(), "🗂️  Temporary files kept in %s\n", ws.Dir)
+	}
+}
+
+// transcriptionModel names the backend and model transcribing this run, e.g.
+// huggingface:openai/whisper-base, or "none" with --skip-audio
+func transcriptionModel() string {
+	if skipAudio {
+		return "none"
+	}
+	backend, model, err := ai.ParseModelSpec(viper.GetString("whisper-model"))
+	if err != nil {
+		return "none"
+	}
+	return backend + ":" + model
+}
+
+// newJobRecord describes a pipeline job for the job database
+func newJobRecord(job *pipeline.Job, aiModel string) *store.Job {
+	// The options are enough to resume a job that failed before any checkpoint
+	params, _ := json.Marshal(job.Options)
+
+	return &store.Job{
+		Name:            job.ID,
+		SourceVideo:     job.Input,
+		OutputDirectory: job.OutputDir(),
+		UserPrompt:      job.Prompt,
+		AIModel:         aiModel,
+		ModelParameters: string(params),
+		MinClipDuration: job.Options.Duration.Min,
+		MaxClipDuration: job.Options.Duration.Max,
+		MaxClips:        job.Options.MaxClips,
+	}
+}
+
+// consoleObserver prints stage progress in the same style as the rest of the CLI
+type consoleObserver struct {
+	progress stageProgress
+}
+
+func newConsoleObserver() *consoleObserver {
+	return &consoleObserver{progress: stageProgress{tty: isTerminal(humanOut())}}
+}
+
+func (o *consoleObserver) StageProgress(job *pipeline.Job, stage pipeline.Stage, done, total float64) {
+	o.progress.update(stage, done, total)
+}
+
+func (o *consoleObserver) StageStarted(job *pipeline.Job, index, total int, stage pipeline.Stage) {
+	o.progress.reset()
+	if !viper.GetBool("quiet") {
+		fmt.Fprintf(humanOut(), "📋 Step %d/%d: %s...\n", index+1, total, stage.Name())
+	}
+}
+
+func (o *consoleObserver) StageFinished(job *pipeline.Job, stage pipeline.Stage, elapsed time.Duration, err error) {
+	o.progress.reset()
+	if err != nil {
+		return
+	}
+	_, isMetadata := stage.(*pipeline.MetadataStage)
+
+	if isMetadata && job.FromCache && !viper.GetBool("quiet") {
+		fmt.Fprintln(humanOut(), "   ♻️  Reusing cached probe and transcript (use --no-cache to redo)")
+	}
+	if !viper.GetBool("verbose") {
+		return
+	}
+	fmt.Fprintf(humanOut(), "   ✅ %s completed (%s)\n", stage.Name(), elapsed.Round(time.Millisecond))
+
+	// Show the probe results once metadata is available
+	if isMetadata {
+		printMediaInfo(job.Media)
+	}
+}
+
+func formatTimestamp(d time.Duration) string {
+	d = d.Round(time.Second)
+	return fmt.Sprintf("%02d:%02d:%02d", int(d.Hours()), int(d.Minutes())%60, int(d.Seconds())%60)
+}
+
+// sceneFilters are the ffmpeg filters scene detection depends on
+var sceneFilters = []string{"scale", "select", "showinfo"}
+
+// reframeFilters lists the ffmpeg filters a reframe mode depends on
+func reframeFilters(mode video.ReframeMode) []string {
+	switch mode {
+	case video.ReframeCenter:
+		return []string{"crop"}
+	case video.ReframeBlurPad:
+		return []string{"split", "scale", "crop", "boxblur", "overlay"}
+	case video.ReframeTrack:
+		// Tracking samples frames with fps, scale and showinfo before cropping
+		return []string{"crop", "sendcmd", "fps", "scale", "showinfo"}
+	default:
+		return nil
+	}
+}
+
+// captionOptions reads caption limits from config, falling back to defaults
+func captionOptions() (captions.Options, error) {
+	opts := captions.DefaultOptions()
+	if viper.IsSet("caption-line-length") {
+		opts.MaxLineLength = viper.GetInt("caption-line-length")
+	}
+	if viper.IsSet("caption-lines") {
+		opts.MaxLines = viper.GetInt("caption-lines")
+	}
+	if viper.IsSet("caption-cps") {
+		opts.MaxCPS = viper.GetFloat64("caption-cps")
+	}
+	if err := opts.Validate(); err != nil {
+		return captions.Options{}, fmt.Errorf("invalid caption settings: %w", err)
+	}
+	return opts, nil
+}
+
+// sceneThreshold reads the scene change threshold from config; 0 turns scene
+// detection off
+func sceneThreshold() float64 {
+	if viper.IsSet("scene-threshold") {
+		return viper.GetFloat64("scene-threshold")
+	}
+	return video.DefaultSceneThreshold
+}
+
+// chunkOptions reads audio chunking settings from config, falling back to defaults
+func chunkOptions() video.ChunkOptions {
+	opts := video.ChunkOptions{
+		Length:  video.DefaultChunkLength,
+		Overlap: video.DefaultChunkOverlap,
+	}
+	if viper.IsSet("chunk-length") {
+		opts.Length = viper.GetDuration("chunk-length")
+	}
+	if viper.IsSet("chunk-overlap") {
+		opts.Overlap = viper.GetDuration("chunk-overlap")
+	}
+	return opts
+}
+
 func validateVideoFile(filename string) error {
 	// Check if file exists
 	if _, err := os.Stat(filename); os.IsNotExist(err) {
@@ -138,3 +542,57 @@ func validateVideoFile(filename string) error {
 	return fmt.Errorf("unsupported file format: %s (supported: %s)",
 		ext, strings.Join(validExts, ", "))
 }
+
+func printEncodeSettings(s video.EncodeSettings) {
+	maxHeight := "source"
+	if s.MaxHeight > 0 {
+		maxHeight = fmt.Sprintf("%dp", s.MaxHeight)
+	}
+	fmt.Fprintf(humanOut(), "   Video: %s crf %d, preset %s, max bitrate %s, max height %s\n",
+		s.VideoCodec, s.CRF, s.Preset, orDefault(s.MaxBitrate, "none"), maxHeight)
+	fmt.Fprintf(humanOut(), "   Audio: %s %s\n", s.AudioCodec, orDefault(s.AudioBitrate, "default bitrate"))
+}
+
+func orDefault(value, fallback string) string {
+	if value == "" {
+		return fallback
+	}
+	return value
+}
+
+func printMediaInfo(info video.MediaInfo) {
+	fmt.Fprintln(humanOut(), "📊 File Information:")
+	fmt.Fprintf(humanOut(), "   Filename: %s\n", info.Filename)
+	fmt.Fprintf(humanOut(), "   Format: %s (%s)\n", info.FormatName, info.FormatLongName)
+	fmt.Fprintf(humanOut(), "   Duration: %s\n", info.Duration)
+	fmt.Fprintf(humanOut(), "   Size: %d bytes\n", info.Size)
+	fmt.Fprintf(humanOut(), "   Bit Rate: %d bps\n", info.BitRate)
+
+	fmt.Fprintf(humanOut(), "\n🎬 Streams (%d total):\n", len(info.Streams))
+
+	for _, stream := range info.Streams {
+		fmt.Fprintf(humanOut(), "\n   Stream %d (%s):\n", stream.Index, stream.CodecType)
+		fmt.Fprintf(humanOut(), "      Codec: %s (%s)\n", stream.CodecName, stream.CodecLongName)
+
+		if stream.CodecType == "video" {
+			fmt.Fprintf(humanOut(), "      Resolution: %dx%d\n", stream.Width, stream.Height)
+			fmt.Fprintf(humanOut(), "      Frame Rate: %.2f fps\n", stream.FrameRate)
+			if stream.Rotation != 0 {
+				fmt.Fprintf(humanOut(), "      Rotation: %d°\n", stream.Rotation)
+			}
+		}
+
+		if stream.CodecType == "audio" {
+			fmt.Fprintf(humanOut(), "      Sample Rate: %d Hz\n", stream.SampleRate)
+			fmt.Fprintf(humanOut(), "      Channels: %d\n", stream.Channels)
+		}
+
+		if stream.Language != "" {
+			fmt.Fprintf(humanOut(), "      Language: %s\n", stream.Language)
+		}
+		if stream.Duration > 0 {
+			fmt.Fprintf(humanOut(), "      Duration: %s\n", stream.Duration)
+		}
+	}
+	fmt.Fprintln(humanOut())
+}
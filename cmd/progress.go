@@ -0,0 +1,70 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"ai-video-editor/processing/pipeline"
+
+	"github.com/schollz/progressbar/v3"
+	"github.com/spf13/viper"
+)
+
+// isTerminal reports whether f is an interactive terminal rather than a pipe or file
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// stageProgress shows progress within a stage as a bar on a terminal, or as
+// a log line every few percent when output is redirected
+type stageProgress struct {
+	tty    bool
+	bar    *progressbar.ProgressBar
+	logged int // Last percentage logged without a terminal
+}
+
+func (p *stageProgress) update(stage pipeline.Stage, done, total float64) {
+	if viper.GetBool("quiet") {
+		return
+	}
+	percent := int(done / total * 100)
+
+	if p.tty {
+		if p.bar == nil {
+			p.bar = progressbar.NewOptions(100,
+				progressbar.OptionSetWriter(humanOut()),
+				progressbar.OptionSetDescription("   ⏳ "+stage.Name()),
+				progressbar.OptionSetWidth(30),
+				progressbar.OptionSetPredictTime(true),
+				progressbar.OptionThrottle(100*time.Millisecond),
+				progressbar.OptionClearOnFinish(),
+			)
+		}
+		p.bar.Set(percent)
+		return
+	}
+
+	// Plain lines for logs and CI, more often with --verbose
+	step := 25
+	if viper.GetBool("verbose") {
+		step = 10
+	}
+	if percent >= p.logged+step || (percent == 100 && p.logged < 100) {
+		p.logged = percent - percent%step
+		if percent == 100 {
+			p.logged = 100
+		}
+		fmt.Fprintf(humanOut(), "   ⏳ %s: %d%%\n", stage.Name(), percent)
+	}
+}
+
+// reset clears the bar at the end of a stage
+func (p *stageProgress) reset() {
+	if p.bar != nil {
+		p.bar.Finish()
+		p.bar = nil
+	}
+	p.logged = 0
+}
@@ -0,0 +1,157 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+
+	"ai-video-editor/processing/ai"
+	"ai-video-editor/processing/pipeline"
+	"ai-video-editor/processing/store"
+	"ai-video-editor/processing/toolchain"
+	"ai-video-editor/processing/workspace"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var (
+	resumeKeepTemp bool
+	resumeForce    bool
+)
+
+var resumeCmd = &cobra.Command{
+	Use:   "resume [job id]",
+	Short: "Continue a failed or interrupted job",
+	Long: `Resume picks a job back up from its last completed stage, with the settings it
+was started with. Finished work such as the transcript is restored from the
+job's checkpoint, and only clips that failed to encode are extracted again.
+
+A job recorded as running is refused, since another process may still be
+working on it. If that process was killed without recording an outcome, pass
+--force to resume it anyway.
+
+The job id is shown when a run fails and by ` + "`ai-editor jobs list`" + `.`,
+	Args: cobra.ExactArgs(1),
+	Example: `  # Resume after Ctrl-C or a failed clip encode
+  ai-editor resume 20250808-142501-3f9a1c2b`,
+	RunE: runResume,
+}
+
+func init() {
+	rootCmd.AddCommand(resumeCmd)
+
+	resumeCmd.Flags().BoolVar(&resumeKeepTemp, "keep-temp", false, "keep the job's temporary files for debugging")
+	resumeCmd.Flags().BoolVar(&resumeForce, "force", false, "resume a job recorded as running whose process is gone")
+}
+
+func runResume(cmd *cobra.Command, args []string) error {
+	db, err := store.Open(store.DefaultPath())
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	record, err := findJob(cmd.Context(), db, args[0])
+	if err != nil {
+		return err
+	}
+	if record.Status == store.StatusCompleted {
+		return fmt.Errorf("job %s already completed", record.Name)
+	}
+	if record.Status == store.StatusRunning && !resumeForce {
+		return fmt.Errorf("job %s is still running (use --force if its process was killed)", record.Name)
+	}
+	if _, err := os.Stat(record.SourceVideo); err != nil {
+		return fmt.Errorf("source video of job %s is no longer available: %w", record.Name, err)
+	}
+
+	// Cancel any running ffmpeg process on Ctrl-C or SIGTERM
+	ctx, stop := interruptContext(cmd.Context())
+	defer stop()
+
+	// The original workspace is gone, so the resumed run gets a fresh one
+	ws, err := workspace.New(tempDir(), resumeKeepTemp)
+	if err != nil {
+		return err
+	}
+	defer cleanupWorkspace(ws)
+
+	job := &pipeline.Job{
+		ID:        record.Name,
+		Input:     record.SourceVideo,
+		Prompt:    record.UserPrompt,
+		Workspace: ws,
+		Cache:     db,
+	}
+
+	runner := pipeline.NewRunner(nil)
+	start, err := pipeline.RestoreJob(ctx, db, record, job)
+	if err != nil {
+		return err
+	}
+	if start >= len(runner.Stages) {
+		return fmt.Errorf("job %s has no stages left to run", record.Name)
+	}
+
+	// Check the tools against the restored options, as process does
+	job.Toolchain, err = toolchain.Discover(toolchain.DefaultOptions())
+	if err != nil {
+		return err
+	}
+	if err := preflight(job.Toolchain, job.Options); err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(job.Options.OutputDir, 0755); err != nil {
+		return fmt.Errorf("failed to create output directory: %w", err)
+	}
+
+	// Transcribe with the model the job was started with
+	if !job.Options.SkipAudio && needsTranscriber(runner.Stages[start:]) {
+		cfg := ai.TranscriberConfigFromViper()
+		cfg.Model = record.AIModel
+		job.Transcriber, err = ai.NewTranscriber(cfg)
+		if err != nil {
+			return fmt.Errorf("transcription unavailable: %w", err)
+		}
+	}
+
+	if !viper.GetBool("quiet") {
+		fmt.Fprintf(humanOut(), "⏯️  Resuming job %s at step %d/%d: %s\n", record.Name, start+1, len(runner.Stages), runner.Stages[start].Name())
+		fmt.Fprintf(humanOut(), "Input: %s\n", job.Input)
+		fmt.Fprintf(humanOut(), "Prompt: %s\n", job.Prompt)
+		if n := len(job.Clips); n > 0 {
+			fmt.Fprintf(humanOut(), "Clips already extracted: %d\n", n)
+		}
+		fmt.Fprintln(humanOut())
+	}
+
+	return executeJob(ctx, db, record, job, runner, start)
+}
+
+// needsTranscriber reports whether any of the stages transcribes audio
+func needsTranscriber(stages []pipeline.Stage) bool {
+	for _, stage := range stages {
+		if _, ok := stage.(*pipeline.TranscriptionStage); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// findJob looks a job up by its workspace id or its numeric database id
+func findJob(ctx context.Context, db *store.Store, id string) (*store.Job, error) {
+	job, err := db.GetJobByName(ctx, id)
+	if errors.Is(err, store.ErrNotFound) {
+		if n, convErr := strconv.ParseInt(id, 10, 64); convErr == nil {
+			job, err = db.GetJob(ctx, n)
+		}
+	}
+	if errors.Is(err, store.ErrNotFound) {
+		return nil, fmt.Errorf("no job with id %s (see `ai-editor jobs list`)", id)
+	}
+	return job, err
+}
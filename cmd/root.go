@@ -30,13 +30,18 @@ Features:
   ai-editor process lecture.mp4 "educational highlights" --duration 30s
   
   # Configure your AI API key
-  ai-editor config set api-key sk-your-openai-key`,
+  ai-editor config set api-key sk-your-openai-key
+
+  # Machine-readable events for automation, one JSON object per line
+  ai-editor process video.mp4 "find funny moments" --output-format ndjson`,
+	PersistentPreRunE: validateOutputFormat,
 }
 
 // Execute adds all child commands to the root command and sets flags appropriately.
 func Execute() {
-	err := rootCmd.Execute()
+	cmd, err := rootCmd.ExecuteC()
 	if err != nil {
+		reportFailure(cmd, err)
 		os.Exit(1)
 	}
 }
@@ -48,10 +53,12 @@ func init() {
 	rootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", "config file (default is $HOME/.ai-editor.yaml)")
 	rootCmd.PersistentFlags().BoolP("verbose", "v", false, "verbose output")
 	rootCmd.PersistentFlags().BoolP("quiet", "q", false, "quiet mode (minimal output)")
+	rootCmd.PersistentFlags().String("output-format", outputText, "output format: text, json (a summary at the end) or ndjson (an event per line); messages go to stderr for json and ndjson")
 
 	// Bind flags to viper
 	viper.BindPFlag("verbose", rootCmd.PersistentFlags().Lookup("verbose"))
 	viper.BindPFlag("quiet", rootCmd.PersistentFlags().Lookup("quiet"))
+	viper.BindPFlag("output-format", rootCmd.PersistentFlags().Lookup("output-format"))
 }
 
 // initConfig reads in config file and ENV variables if set.
@@ -16,17 +16,31 @@ var versionCmd = &cobra.Command{
 	Use:   "version",
 	Short: "Show version information",
 	Long:  `Display version information for AI Video Editor including build details.`,
-	Run:   runVersion,
+	RunE:  runVersion,
 }
 
 func init() {
 	rootCmd.AddCommand(versionCmd)
 }
 
-func runVersion(cmd *cobra.Command, args []string) {
+// versionInfo is version as reported by --output-format json and ndjson
+type versionInfo struct {
+	Version   string `json:"version"`
+	Built     string `json:"built"`
+	GoVersion string `json:"go_version"`
+	OS        string `json:"os"`
+	Arch      string `json:"arch"`
+}
+
+func runVersion(cmd *cobra.Command, args []string) error {
+	if structuredOutput() {
+		return writeStructured(versionInfo{version, date, runtime.Version(), runtime.GOOS, runtime.GOARCH})
+	}
+
 	fmt.Printf("AI Video Editor\n")
 	fmt.Printf("Version:    %s\n", version)
 	fmt.Printf("Built:      %s\n", date)
 	fmt.Printf("Go version: %s\n", runtime.Version())
 	fmt.Printf("OS/Arch:    %s/%s\n", runtime.GOOS, runtime.GOARCH)
+	return nil
 }
\ No newline at end of file
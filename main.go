@@ -24,10 +24,10 @@ func main() {
     if err := viper.ReadInConfig(); err != nil {
         if _, ok := err.(viper.ConfigFileNotFoundError); ok {
             // Config file not found; ignore error
-            fmt.Println("No config file found, using defaults")
+            fmt.Fprintln(os.Stderr, "No config file found, using defaults")
         } else {
             // Config file was found but another error was produced
-            fmt.Printf("Error reading config file: %v\n", err)
+            fmt.Fprintf(os.Stderr, "Error reading config file: %v\n", err)
         }
     }
 
@@ -0,0 +1,79 @@
+package ai
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"os"
+)
+
+// Layout of the speech audio every backend is given: 16kHz mono 16-bit PCM
+const (
+	AudioSampleRate = 16000
+	audioBits       = 16
+)
+
+// Audio is speech to transcribe, either a WAV file produced by the audio
+// extractor or samples decoded into memory from the audio stream
+type Audio struct {
+	Path    string
+	Samples []int16 // 16kHz mono, used when Path is empty
+}
+
+// WAV returns the audio as the contents of a WAV file
+func (a Audio) WAV() ([]byte, error) {
+	if a.Path != "" {
+		data, err := os.ReadFile(a.Path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read audio file: %w", err)
+		}
+		return data, nil
+	}
+
+	dataSize := uint32(len(a.Samples) * audioBits / 8)
+	header := wavHeader{
+		Riff:          [4]byte{'R', 'I', 'F', 'F'},
+		RiffSize:      36 + dataSize,
+		Wave:          [4]byte{'W', 'A', 'V', 'E'},
+		Fmt:           [4]byte{'f', 'm', 't', ' '},
+		FmtSize:       16,
+		Format:        1, // PCM
+		Channels:      1,
+		SampleRate:    AudioSampleRate,
+		ByteRate:      AudioSampleRate * audioBits / 8,
+		BlockAlign:    audioBits / 8,
+		BitsPerSample: audioBits,
+		Data:          [4]byte{'d', 'a', 't', 'a'},
+		DataSize:      dataSize,
+	}
+
+	buf := bytes.NewBuffer(make([]byte, 0, 44+int(dataSize)))
+	binary.Write(buf, binary.LittleEndian, header)
+	binary.Write(buf, binary.LittleEndian, a.Samples)
+	return buf.Bytes(), nil
+}
+
+// wavHeader is the 44 byte header of a canonical PCM WAV file
+type wavHeader struct {
+	Riff          [4]byte
+	RiffSize      uint32
+	Wave          [4]byte
+	Fmt           [4]byte
+	FmtSize       uint32
+	Format        uint16
+	Channels      uint16
+	SampleRate    uint32
+	ByteRate      uint32
+	BlockAlign    uint16
+	BitsPerSample uint16
+	Data          [4]byte
+	DataSize      uint32
+}
+
+// Name returns a file name for the audio, for APIs that want one with uploads
+func (a Audio) Name() string {
+	if a.Path != "" {
+		return a.Path
+	}
+	return "audio.wav"
+}
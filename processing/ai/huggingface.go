@@ -0,0 +1,137 @@
+package ai
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"ai-video-editor/processing/retry"
+
+	"github.com/Kardbord/hfapigo/v3"
+)
+
+// HuggingFaceTranscriber sends audio to the Hugging Face inference API
+type HuggingFaceTranscriber struct {
+	APIKey string
+	Model  string
+	Retry  retry.Policy
+	Client *http.Client
+}
+
+// NewHuggingFaceTranscriber creates a Hugging Face backend for the given model repo
+func NewHuggingFaceTranscriber(apiKey, model string) (*HuggingFaceTranscriber, error) {
+	if apiKey == "" {
+		return nil, fmt.Errorf("hugging face backend needs an API key (ai-editor config set api-key <key>)")
+	}
+	if model == "" {
+		model = hfapigo.RecommendedSpeechRecongnitionModelEnglish
+	}
+
+	return &HuggingFaceTranscriber{
+		APIKey: apiKey,
+		Model:  model,
+		Retry:  retry.DefaultPolicy(),
+		Client: &http.Client{},
+	}, nil
+}
+
+// Name returns the backend name
+func (t *HuggingFaceTranscriber) Name() string {
+	return BackendHuggingFace
+}
+
+// huggingFaceResponse is the ASR pipeline output with return_timestamps=word
+type huggingFaceResponse struct {
+	Text   string `json:"text"`
+	Chunks []struct {
+		Text      string     `json:"text"`
+		Timestamp []*float64 `json:"timestamp"` // [start, end]; end may be null for the last word
+	} `json:"chunks"`
+}
+
+// Transcribe sends the audio file to the model, retrying while the model loads
+// or the API is rate limiting
+func (t *HuggingFaceTranscriber) Transcribe(ctx context.Context, audio Audio) (Transcript, error) {
+	data, err := audio.WAV()
+	if err != nil {
+		return Transcript{}, err
+	}
+
+	// hfapigo only sends raw bytes and returns plain text, so build the JSON
+	// request ourselves to ask for word timestamps
+	payload, err := json.Marshal(map[string]any{
+		"inputs": base64.StdEncoding.EncodeToString(data),
+		"parameters": map[string]any{
+			"return_timestamps": "word",
+		},
+	})
+	if err != nil {
+		return Transcript{}, fmt.Errorf("failed to encode request: %w", err)
+	}
+
+	body, err := retry.DoValue(ctx, t.Retry, func(ctx context.Context) ([]byte, error) {
+		return t.send(ctx, payload)
+	})
+	if err != nil {
+		return Transcript{}, fmt.Errorf("hugging face transcription failed: %w", err)
+	}
+
+	var parsed huggingFaceResponse
+	if err := json.Unmarshal(body, &parsed); err != nil {
+		return Transcript{}, fmt.Errorf("failed to parse transcription response: %w", err)
+	}
+
+	var words []Word
+	for i, c := range parsed.Chunks {
+		if len(c.Timestamp) < 1 || c.Timestamp[0] == nil {
+			continue
+		}
+		w := Word{
+			Text:  strings.TrimSpace(c.Text),
+			Start: secondsToDuration(*c.Timestamp[0]),
+		}
+		switch {
+		case len(c.Timestamp) > 1 && c.Timestamp[1] != nil:
+			w.End = secondsToDuration(*c.Timestamp[1])
+		case i+1 < len(parsed.Chunks) && len(parsed.Chunks[i+1].Timestamp) > 0 && parsed.Chunks[i+1].Timestamp[0] != nil:
+			w.End = secondsToDuration(*parsed.Chunks[i+1].Timestamp[0])
+		default:
+			w.End = w.Start
+		}
+		words = append(words, w)
+	}
+
+	return Transcript{
+		Text:     strings.TrimSpace(parsed.Text),
+		Segments: assignWords(nil, words),
+	}, nil
+}
+
+func (t *HuggingFaceTranscriber) send(ctx context.Context, payload []byte) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, hfapigo.APIBaseURL+t.Model, bytes.NewReader(payload))
+	if err != nil {
+		return nil, retry.Fatal(fmt.Errorf("failed to create request: %w", err))
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(hfapigo.AuthHeaderKey, hfapigo.AuthHeaderPrefix+t.APIKey)
+
+	resp, err := t.Client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, retry.Retryable(fmt.Errorf("failed to read response: %w", err))
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, retry.HTTPError(resp, body)
+	}
+	return body, nil
+}
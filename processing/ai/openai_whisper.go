@@ -0,0 +1,194 @@
+package ai
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"ai-video-editor/processing/retry"
+)
+
+// DefaultOpenAIBaseURL is used when whisper-api-url is not set
+const DefaultOpenAIBaseURL = "https://api.openai.com/v1"
+
+// OpenAITranscriber calls an OpenAI-compatible /audio/transcriptions endpoint.
+// This covers OpenAI itself as well as self-hosted servers such as
+// faster-whisper-server or LocalAI.
+type OpenAITranscriber struct {
+	BaseURL  string
+	APIKey   string
+	Model    string
+	Language string
+	Retry    retry.Policy
+	Client   *http.Client
+}
+
+// NewOpenAITranscriber creates an OpenAI-compatible backend
+func NewOpenAITranscriber(baseURL, apiKey, model, language string) (*OpenAITranscriber, error) {
+	baseURL = openAIBaseURL(baseURL)
+	if apiKey == "" && baseURL == DefaultOpenAIBaseURL {
+		return nil, fmt.Errorf("openai backend needs an API key (ai-editor config set openai-api-key <key>)")
+	}
+
+	return &OpenAITranscriber{
+		BaseURL:  baseURL,
+		APIKey:   apiKey,
+		Model:    model,
+		Language: language,
+		Retry:    retry.DefaultPolicy(),
+		Client:   &http.Client{},
+	}, nil
+}
+
+func openAIBaseURL(baseURL string) string {
+	if baseURL == "" {
+		return DefaultOpenAIBaseURL
+	}
+	return strings.TrimRight(baseURL, "/")
+}
+
+// Name returns the backend name
+func (t *OpenAITranscriber) Name() string {
+	return BackendOpenAI
+}
+
+// openAIResponse is the verbose_json transcription response
+type openAIResponse struct {
+	Text     string `json:"text"`
+	Language string `json:"language"`
+	Segments []struct {
+		Start float64 `json:"start"`
+		End   float64 `json:"end"`
+		Text  string  `json:"text"`
+	} `json:"segments"`
+	Words []struct {
+		Word  string  `json:"word"`
+		Start float64 `json:"start"`
+		End   float64 `json:"end"`
+	} `json:"words"`
+}
+
+// Transcribe uploads the audio file and returns the timed transcript
+func (t *OpenAITranscriber) Transcribe(ctx context.Context, audio Audio) (Transcript, error) {
+	body, contentType, err := t.buildRequestBody(audio)
+	if err != nil {
+		return Transcript{}, err
+	}
+
+	respBody, err := retry.DoValue(ctx, t.Retry, func(ctx context.Context) ([]byte, error) {
+		return t.send(ctx, body, contentType)
+	})
+	if err != nil {
+		return Transcript{}, fmt.Errorf("transcription request failed: %w", err)
+	}
+
+	var parsed openAIResponse
+	if err := json.Unmarshal(respBody, &parsed); err != nil {
+		return Transcript{}, fmt.Errorf("failed to parse transcription response: %w", err)
+	}
+
+	transcript := Transcript{
+		Text:     strings.TrimSpace(parsed.Text),
+		Language: parsed.Language,
+	}
+	for _, s := range parsed.Segments {
+		transcript.Segments = append(transcript.Segments, Segment{
+			Start: secondsToDuration(s.Start),
+			End:   secondsToDuration(s.End),
+			Text:  strings.TrimSpace(s.Text),
+		})
+	}
+
+	// Words come back as a flat list alongside the segments
+	words := make([]Word, 0, len(parsed.Words))
+	for _, w := range parsed.Words {
+		words = append(words, Word{
+			Text:  strings.TrimSpace(w.Word),
+			Start: secondsToDuration(w.Start),
+			End:   secondsToDuration(w.End),
+		})
+	}
+	transcript.Segments = assignWords(transcript.Segments, words)
+
+	return transcript, nil
+}
+
+func (t *OpenAITranscriber) send(ctx context.Context, body []byte, contentType string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, t.BaseURL+"/audio/transcriptions", bytes.NewReader(body))
+	if err != nil {
+		return nil, retry.Fatal(fmt.Errorf("failed to create transcription request: %w", err))
+	}
+	req.Header.Set("Content-Type", contentType)
+	if t.APIKey != "" {
+		req.Header.Set("Authorization", "Bearer "+t.APIKey)
+	}
+
+	resp, err := t.Client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, retry.Retryable(fmt.Errorf("failed to read transcription response: %w", err))
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, retry.HTTPError(resp, respBody)
+	}
+	return respBody, nil
+}
+
+func (t *OpenAITranscriber) buildRequestBody(audio Audio) ([]byte, string, error) {
+	data, err := audio.WAV()
+	if err != nil {
+		return nil, "", err
+	}
+
+	body := &bytes.Buffer{}
+	w := multipart.NewWriter(body)
+
+	part, err := w.CreateFormFile("file", filepath.Base(audio.Name()))
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to build transcription request: %w", err)
+	}
+	if _, err := part.Write(data); err != nil {
+		return nil, "", fmt.Errorf("failed to build transcription request: %w", err)
+	}
+
+	fields := map[string]string{
+		"model":           t.Model,
+		"response_format": "verbose_json",
+	}
+	if t.Language != "" {
+		fields["language"] = t.Language
+	}
+	for k, v := range fields {
+		if err := w.WriteField(k, v); err != nil {
+			return nil, "", fmt.Errorf("failed to build transcription request: %w", err)
+		}
+	}
+
+	// Ask for both word and segment timestamps
+	for _, granularity := range []string{"word", "segment"} {
+		if err := w.WriteField("timestamp_granularities[]", granularity); err != nil {
+			return nil, "", fmt.Errorf("failed to build transcription request: %w", err)
+		}
+	}
+
+	if err := w.Close(); err != nil {
+		return nil, "", fmt.Errorf("failed to build transcription request: %w", err)
+	}
+	return body.Bytes(), w.FormDataContentType(), nil
+}
+
+func secondsToDuration(seconds float64) time.Duration {
+	return time.Duration(seconds * float64(time.Second))
+}
@@ -0,0 +1,158 @@
+package ai
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/viper"
+)
+
+// Transcriber turns speech in an audio file into text
+type Transcriber interface {
+	// Name identifies the backend, e.g. "huggingface"
+	Name() string
+	Transcribe(ctx context.Context, audio Audio) (Transcript, error)
+}
+
+// Backend names accepted as a prefix in the whisper-model config key
+const (
+	BackendHuggingFace = "huggingface"
+	BackendOpenAI      = "openai"
+	BackendWhisperCpp  = "whisper.cpp"
+)
+
+// TranscriberConfig holds everything needed to construct any transcription backend
+type TranscriberConfig struct {
+	// Model selects both backend and model, e.g. "base", "hf:openai/whisper-small",
+	// "openai:whisper-1" or "local:medium"
+	Model    string
+	Language string
+
+	HuggingFaceAPIKey string
+
+	OpenAIAPIKey  string
+	OpenAIBaseURL string
+
+	WhisperCppPath     string
+	WhisperCppModelDir string
+}
+
+// TranscriberConfigFromViper reads transcription settings from the config file
+func TranscriberConfigFromViper() TranscriberConfig {
+	return TranscriberConfig{
+		Model:              viper.GetString("whisper-model"),
+		Language:           viper.GetString("language"),
+		HuggingFaceAPIKey:  viper.GetString("api-key"),
+		OpenAIAPIKey:       viper.GetString("openai-api-key"),
+		OpenAIBaseURL:      viper.GetString("whisper-api-url"),
+		WhisperCppPath:     viper.GetString("whisper-cpp-path"),
+		WhisperCppModelDir: viper.GetString("whisper-cpp-model-dir"),
+	}
+}
+
+// whisperSizes are the model sizes accepted without a backend prefix
+var whisperSizes = map[string]string{
+	"tiny":   "tiny",
+	"base":   "base",
+	"small":  "small",
+	"medium": "medium",
+	"large":  "large-v3",
+}
+
+// ParseModelSpec splits a whisper-model value into backend and model name.
+// Bare sizes ("base", "large") and Hugging Face repo ids use the Hugging Face backend.
+func ParseModelSpec(spec string) (backend, model string, err error) {
+	spec = strings.TrimSpace(spec)
+	if spec == "" {
+		spec = "base"
+	}
+
+	prefix, rest, found := strings.Cut(spec, ":")
+	if !found {
+		if size, ok := whisperSizes[spec]; ok {
+			return BackendHuggingFace, "openai/whisper-" + size, nil
+		}
+		if strings.Contains(spec, "/") {
+			return BackendHuggingFace, spec, nil
+		}
+		return "", "", fmt.Errorf("unknown whisper model %q (use tiny, base, small, medium, large or <backend>:<model>)", spec)
+	}
+
+	if rest == "" {
+		return "", "", fmt.Errorf("whisper model %q is missing a model name after %q", spec, prefix+":")
+	}
+
+	switch prefix {
+	case "hf", "huggingface":
+		if size, ok := whisperSizes[rest]; ok {
+			rest = "openai/whisper-" + size
+		}
+		return BackendHuggingFace, rest, nil
+	case "openai":
+		return BackendOpenAI, rest, nil
+	case "local", "whisper.cpp", "whispercpp":
+		return BackendWhisperCpp, rest, nil
+	default:
+		return "", "", fmt.Errorf("unknown transcription backend %q (use hf, openai or local)", prefix)
+	}
+}
+
+// NewTranscriber builds the backend selected by cfg.Model
+func NewTranscriber(cfg TranscriberConfig) (Transcriber, error) {
+	backend, model, err := ParseModelSpec(cfg.Model)
+	if err != nil {
+		return nil, err
+	}
+
+	switch backend {
+	case BackendHuggingFace:
+		return NewHuggingFaceTranscriber(cfg.HuggingFaceAPIKey, model)
+	case BackendOpenAI:
+		return NewOpenAITranscriber(cfg.OpenAIBaseURL, cfg.OpenAIAPIKey, model, cfg.Language)
+	case BackendWhisperCpp:
+		return NewWhisperCppTranscriber(cfg.WhisperCppPath, cfg.WhisperCppModelDir, model, cfg.Language)
+	default:
+		return nil, fmt.Errorf("unsupported transcription backend %q", backend)
+	}
+}
+
+// BackendStatus reports whether a backend could be used with the current config
+type BackendStatus struct {
+	Backend string
+	Usable  bool
+	Detail  string
+}
+
+// CheckBackends reports which transcription backends are usable, without
+// making any network requests
+func CheckBackends(cfg TranscriberConfig) []BackendStatus {
+	var statuses []BackendStatus
+
+	hf := BackendStatus{Backend: BackendHuggingFace}
+	if cfg.HuggingFaceAPIKey != "" {
+		hf.Usable, hf.Detail = true, "API key configured"
+	} else {
+		hf.Detail = "no API key configured (api-key)"
+	}
+	statuses = append(statuses, hf)
+
+	// A custom base URL usually means a self-hosted server that needs no key
+	oa := BackendStatus{Backend: BackendOpenAI}
+	if cfg.OpenAIAPIKey != "" || (cfg.OpenAIBaseURL != "" && cfg.OpenAIBaseURL != DefaultOpenAIBaseURL) {
+		oa.Usable, oa.Detail = true, "endpoint "+openAIBaseURL(cfg.OpenAIBaseURL)
+	} else {
+		oa.Detail = "no API key configured (openai-api-key)"
+	}
+	statuses = append(statuses, oa)
+
+	wc := BackendStatus{Backend: BackendWhisperCpp}
+	if bin, err := findWhisperCpp(cfg.WhisperCppPath); err == nil {
+		wc.Usable, wc.Detail = true, bin
+	} else {
+		wc.Detail = err.Error()
+	}
+	statuses = append(statuses, wc)
+
+	return statuses
+}
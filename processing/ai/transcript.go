@@ -0,0 +1,209 @@
+package ai
+
+import (
+	"sort"
+	"strings"
+	"time"
+)
+
+// Transcript is the text recognised in a piece of audio, with timing
+type Transcript struct {
+	Text     string
+	Language string
+	Segments []Segment
+}
+
+// Segment is a timed span of a transcript, usually a sentence or phrase.
+// Times are relative to the start of the audio that was transcribed.
+type Segment struct {
+	Start time.Duration
+	End   time.Duration
+	Text  string
+	Words []Word
+}
+
+// Word is a single recognised word. Confidence is in [0,1], or 0 when the
+// backend does not report one.
+type Word struct {
+	Text       string
+	Start      time.Duration
+	End        time.Duration
+	Confidence float64
+}
+
+// ChunkTranscript is the transcript of one audio chunk and where that chunk
+// sits in the source
+type ChunkTranscript struct {
+	Offset     time.Duration
+	Duration   time.Duration
+	Transcript Transcript
+}
+
+// Words returns every word in the transcript in order
+func (t Transcript) Words() []Word {
+	var words []Word
+	for _, s := range t.Segments {
+		words = append(words, s.Words...)
+	}
+	return words
+}
+
+// HasWordTimings reports whether the backend returned per-word timestamps
+func (t Transcript) HasWordTimings() bool {
+	for _, s := range t.Segments {
+		if len(s.Words) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// Shift returns a copy of the transcript with every timestamp moved by offset
+func (t Transcript) Shift(offset time.Duration) Transcript {
+	shifted := Transcript{Text: t.Text, Language: t.Language}
+	for _, s := range t.Segments {
+		seg := Segment{
+			Start: s.Start + offset,
+			End:   s.End + offset,
+			Text:  s.Text,
+		}
+		for _, w := range s.Words {
+			w.Start += offset
+			w.End += offset
+			seg.Words = append(seg.Words, w)
+		}
+		shifted.Segments = append(shifted.Segments, seg)
+	}
+	return shifted
+}
+
+// Slice returns the part of the transcript between start and end. Words are
+// kept when their midpoint falls inside the range; segments are trimmed to
+// the words they keep.
+func (t Transcript) Slice(start, end time.Duration) Transcript {
+	sliced := Transcript{Language: t.Language}
+	for _, s := range t.Segments {
+		if seg, ok := trimSegment(s, start, end); ok {
+			sliced.Segments = append(sliced.Segments, seg)
+		}
+	}
+	sliced.Text = joinSegmentText(sliced.Segments)
+	return sliced
+}
+
+// MergeChunks stitches chunk transcripts into one transcript on the source
+// timeline. Chunks overlap so that words on a boundary are heard whole by at
+// least one of them; each overlap is split at its midpoint and words are taken
+// from whichever chunk owns that side, so nothing is duplicated.
+func MergeChunks(chunks []ChunkTranscript) Transcript {
+	sorted := append([]ChunkTranscript(nil), chunks...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Offset < sorted[j].Offset })
+
+	merged := Transcript{}
+	for i, c := range sorted {
+		if merged.Language == "" {
+			merged.Language = c.Transcript.Language
+		}
+
+		// Window this chunk owns on the source timeline
+		lower := time.Duration(0)
+		if i > 0 {
+			lower = cutPoint(sorted[i-1], c)
+		}
+		upper := time.Duration(1<<63 - 1)
+		if i < len(sorted)-1 {
+			upper = cutPoint(c, sorted[i+1])
+		}
+
+		shifted := c.Transcript.Shift(c.Offset)
+		for _, s := range shifted.Segments {
+			if seg, ok := trimSegment(s, lower, upper); ok {
+				merged.Segments = append(merged.Segments, seg)
+			}
+		}
+	}
+
+	merged.Text = joinSegmentText(merged.Segments)
+	return merged
+}
+
+// cutPoint is the middle of the overlap between two consecutive chunks, or
+// the start of the next chunk if they don't overlap
+func cutPoint(prev, next ChunkTranscript) time.Duration {
+	prevEnd := prev.Offset + prev.Duration
+	if prev.Duration <= 0 || prevEnd <= next.Offset {
+		return next.Offset
+	}
+	return next.Offset + (prevEnd-next.Offset)/2
+}
+
+// trimSegment keeps the part of a segment whose words fall in [lower, upper).
+// Segments without word timings are kept or dropped whole by their midpoint.
+func trimSegment(s Segment, lower, upper time.Duration) (Segment, bool) {
+	if len(s.Words) == 0 {
+		mid := s.Start + (s.End-s.Start)/2
+		return s, mid >= lower && mid < upper
+	}
+
+	var kept []Word
+	for _, w := range s.Words {
+		mid := w.Start + (w.End-w.Start)/2
+		if mid >= lower && mid < upper {
+			kept = append(kept, w)
+		}
+	}
+	if len(kept) == 0 {
+		return Segment{}, false
+	}
+	if len(kept) == len(s.Words) {
+		return s, true
+	}
+
+	texts := make([]string, len(kept))
+	for i, w := range kept {
+		texts[i] = w.Text
+	}
+	return Segment{
+		Start: kept[0].Start,
+		End:   kept[len(kept)-1].End,
+		Text:  strings.Join(texts, " "),
+		Words: kept,
+	}, true
+}
+
+func joinSegmentText(segments []Segment) string {
+	texts := make([]string, 0, len(segments))
+	for _, s := range segments {
+		if s.Text != "" {
+			texts = append(texts, s.Text)
+		}
+	}
+	return strings.Join(texts, " ")
+}
+
+// assignWords distributes words into the segment whose time range contains
+// their midpoint, for backends that return words and segments separately
+func assignWords(segments []Segment, words []Word) []Segment {
+	if len(segments) == 0 && len(words) > 0 {
+		texts := make([]string, len(words))
+		for i, w := range words {
+			texts[i] = w.Text
+		}
+		return []Segment{{
+			Start: words[0].Start,
+			End:   words[len(words)-1].End,
+			Text:  strings.Join(texts, " "),
+			Words: words,
+		}}
+	}
+
+	si := 0
+	for _, w := range words {
+		mid := w.Start + (w.End-w.Start)/2
+		for si < len(segments)-1 && mid >= segments[si].End {
+			si++
+		}
+		segments[si].Words = append(segments[si].Words, w)
+	}
+	return segments
+}
@@ -0,0 +1,110 @@
+package ai
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestMergeChunks(t *testing.T) {
+	const s = time.Second
+	word := func(text string, start, end time.Duration) Word {
+		return Word{Text: text, Start: start, End: end}
+	}
+	segment := func(text string, words ...Word) Segment {
+		return Segment{Start: words[0].Start, End: words[len(words)-1].End, Text: text, Words: words}
+	}
+
+	// Two 10s chunks overlapping by 2s, both hearing "dup" at 8.5s-9.5s of the
+	// source. The overlap is split at 9s, so "dup" belongs to the second chunk.
+	first := ChunkTranscript{Offset: 0, Duration: 10 * s, Transcript: Transcript{
+		Language: "en",
+		Segments: []Segment{segment("a dup", word("a", 1*s, 2*s), word("dup", 8500*time.Millisecond, 9500*time.Millisecond))},
+	}}
+	second := ChunkTranscript{Offset: 8 * s, Duration: 10 * s, Transcript: Transcript{
+		Language: "en",
+		Segments: []Segment{segment("dup b", word("dup", 500*time.Millisecond, 1500*time.Millisecond), word("b", 3*s, 4*s))},
+	}}
+	overlapWords := []Word{
+		word("a", 1*s, 2*s),
+		word("dup", 8500*time.Millisecond, 9500*time.Millisecond),
+		word("b", 11*s, 12*s),
+	}
+
+	tests := []struct {
+		name      string
+		chunks    []ChunkTranscript
+		wantText  string
+		wantLang  string
+		wantWords []Word
+	}{
+		{
+			name:      "no chunks",
+			chunks:    nil,
+			wantText:  "",
+			wantWords: nil,
+		},
+		{
+			name: "single chunk shifted to its offset",
+			chunks: []ChunkTranscript{{Offset: 60 * s, Duration: 10 * s, Transcript: Transcript{
+				Language: "de",
+				Segments: []Segment{segment("hallo", word("hallo", 1*s, 2*s))},
+			}}},
+			wantText:  "hallo",
+			wantLang:  "de",
+			wantWords: []Word{word("hallo", 61*s, 62*s)},
+		},
+		{
+			name:      "overlap split at its midpoint",
+			chunks:    []ChunkTranscript{first, second},
+			wantText:  "a dup b",
+			wantLang:  "en",
+			wantWords: overlapWords,
+		},
+		{
+			name:      "chunks sorted by offset",
+			chunks:    []ChunkTranscript{second, first},
+			wantText:  "a dup b",
+			wantLang:  "en",
+			wantWords: overlapWords,
+		},
+		{
+			name: "segments without word timings kept by midpoint",
+			chunks: []ChunkTranscript{
+				{Offset: 0, Duration: 10 * s, Transcript: Transcript{Segments: []Segment{
+					{Start: 2 * s, End: 4 * s, Text: "early"},
+					{Start: 8 * s, End: 10 * s, Text: "late"}, // Midpoint 9s, past the cut
+				}}},
+				{Offset: 8 * s, Duration: 10 * s, Transcript: Transcript{Segments: []Segment{
+					{Start: 0, End: 2 * s, Text: "repeat"}, // Midpoint 9s on the source
+				}}},
+			},
+			wantText:  "early repeat",
+			wantWords: nil,
+		},
+		{
+			name: "chunks of unknown length cut where the next starts",
+			chunks: []ChunkTranscript{
+				{Offset: 0, Transcript: Transcript{Segments: []Segment{segment("x y", word("x", 1*s, 2*s), word("y", 5*s, 6*s))}}},
+				{Offset: 5 * s, Transcript: Transcript{Segments: []Segment{segment("z", word("z", 1*s, 2*s))}}},
+			},
+			wantText:  "x z",
+			wantWords: []Word{word("x", 1*s, 2*s), word("z", 6*s, 7*s)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := MergeChunks(tt.chunks)
+			if got.Text != tt.wantText {
+				t.Errorf("MergeChunks() text = %q, want %q", got.Text, tt.wantText)
+			}
+			if got.Language != tt.wantLang {
+				t.Errorf("MergeChunks() language = %q, want %q", got.Language, tt.wantLang)
+			}
+			if words := got.Words(); !reflect.DeepEqual(words, tt.wantWords) {
+				t.Errorf("MergeChunks() words = %+v, want %+v", words, tt.wantWords)
+			}
+		})
+	}
+}
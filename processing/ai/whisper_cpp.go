@@ -0,0 +1,207 @@
+package ai
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// whisperCppBinaries are the executable names whisper.cpp has shipped under
+var whisperCppBinaries = []string{"whisper-cli", "whisper-cpp", "whisper"}
+
+// WhisperCppTranscriber runs a local whisper.cpp binary, so no audio leaves the machine
+type WhisperCppTranscriber struct {
+	BinaryPath string
+	ModelPath  string
+	Language   string
+}
+
+// NewWhisperCppTranscriber creates a whisper.cpp backend. model is either a
+// path to a ggml model file or a size (tiny, base, ...) looked up in modelDir.
+func NewWhisperCppTranscriber(binaryPath, modelDir, model, language string) (*WhisperCppTranscriber, error) {
+	bin, err := findWhisperCpp(binaryPath)
+	if err != nil {
+		return nil, err
+	}
+
+	modelPath := model
+	if size, ok := whisperSizes[model]; ok {
+		if modelDir == "" {
+			home, err := os.UserHomeDir()
+			if err != nil {
+				return nil, fmt.Errorf("failed to get home directory: %w", err)
+			}
+			modelDir = filepath.Join(home, ".ai-editor", "models")
+		}
+		modelPath = filepath.Join(modelDir, "ggml-"+size+".bin")
+	}
+	if _, err := os.Stat(modelPath); err != nil {
+		return nil, fmt.Errorf("whisper.cpp model not found at %s (download one with whisper.cpp's models/download-ggml-model.sh)", modelPath)
+	}
+
+	if language == "" {
+		language = "auto"
+	}
+
+	return &WhisperCppTranscriber{
+		BinaryPath: bin,
+		ModelPath:  modelPath,
+		Language:   language,
+	}, nil
+}
+
+// findWhisperCpp resolves the whisper.cpp executable from config or $PATH
+func findWhisperCpp(configured string) (string, error) {
+	if configured != "" {
+		if _, err := os.Stat(configured); err != nil {
+			return "", fmt.Errorf("whisper.cpp binary not found at %s", configured)
+		}
+		return configured, nil
+	}
+	for _, name := range whisperCppBinaries {
+		if p, err := exec.LookPath(name); err == nil {
+			return p, nil
+		}
+	}
+	return "", fmt.Errorf("whisper.cpp binary not found in $PATH (set whisper-cpp-path)")
+}
+
+// Name returns the backend name
+func (t *WhisperCppTranscriber) Name() string {
+	return BackendWhisperCpp
+}
+
+// whisperCppOffsets are millisecond offsets from the start of the audio
+type whisperCppOffsets struct {
+	From int64 `json:"from"`
+	To   int64 `json:"to"`
+}
+
+// whisperCppOutput is the file written by whisper.cpp's -ojf flag
+type whisperCppOutput struct {
+	Result struct {
+		Language string `json:"language"`
+	} `json:"result"`
+	Transcription []struct {
+		Offsets whisperCppOffsets `json:"offsets"`
+		Text    string            `json:"text"`
+		Tokens  []struct {
+			Text    string            `json:"text"`
+			Offsets whisperCppOffsets `json:"offsets"`
+			P       float64           `json:"p"`
+		} `json:"tokens"`
+	} `json:"transcription"`
+}
+
+// Transcribe runs whisper.cpp on the audio and parses its JSON output.
+// In-memory audio is piped to whisper.cpp's stdin rather than written to disk.
+func (t *WhisperCppTranscriber) Transcribe(ctx context.Context, audio Audio) (Transcript, error) {
+	input := audio.Path
+	var stdin []byte
+	if input == "" {
+		wav, err := audio.WAV()
+		if err != nil {
+			return Transcript{}, err
+		}
+		input, stdin = "-", wav
+	}
+
+	// whisper.cpp appends .json to the output base name
+	outDir, err := os.MkdirTemp("", "whisper-cpp-*")
+	if err != nil {
+		return Transcript{}, fmt.Errorf("failed to create whisper.cpp output directory: %w", err)
+	}
+	defer os.RemoveAll(outDir)
+	outBase := filepath.Join(outDir, "transcript")
+	outPath := outBase + ".json"
+
+	cmd := exec.CommandContext(ctx, t.BinaryPath,
+		"-m", t.ModelPath,
+		"-f", input,
+		"-l", t.Language,
+		"-ojf", // Full JSON including per-token timings
+		"-of", outBase,
+		"-np", // No progress or timing prints
+	)
+	if stdin != nil {
+		cmd.Stdin = bytes.NewReader(stdin)
+	}
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		if ctx.Err() != nil {
+			return Transcript{}, ctx.Err()
+		}
+		return Transcript{}, fmt.Errorf("whisper.cpp failed: %w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+
+	data, err := os.ReadFile(outPath)
+	if err != nil {
+		return Transcript{}, fmt.Errorf("failed to read whisper.cpp output: %w", err)
+	}
+
+	var parsed whisperCppOutput
+	if err := json.Unmarshal(data, &parsed); err != nil {
+		return Transcript{}, fmt.Errorf("failed to parse whisper.cpp output: %w", err)
+	}
+
+	transcript := Transcript{Language: parsed.Result.Language}
+	var text []string
+	for _, s := range parsed.Transcription {
+		segText := strings.TrimSpace(s.Text)
+		seg := Segment{
+			Start: time.Duration(s.Offsets.From) * time.Millisecond,
+			End:   time.Duration(s.Offsets.To) * time.Millisecond,
+			Text:  segText,
+		}
+
+		// Tokens are sub-word pieces; a leading space starts a new word.
+		// Special tokens such as [_BEG_] and [_TT_123] carry no text.
+		var current *Word
+		var probs []float64
+		flush := func() {
+			if current != nil && current.Text != "" {
+				current.Confidence = mean(probs)
+				seg.Words = append(seg.Words, *current)
+			}
+			current, probs = nil, nil
+		}
+		for _, tok := range s.Tokens {
+			if strings.HasPrefix(tok.Text, "[_") {
+				continue
+			}
+			if current == nil || strings.HasPrefix(tok.Text, " ") {
+				flush()
+				current = &Word{Start: time.Duration(tok.Offsets.From) * time.Millisecond}
+			}
+			current.Text += strings.TrimSpace(tok.Text)
+			current.End = time.Duration(tok.Offsets.To) * time.Millisecond
+			probs = append(probs, tok.P)
+		}
+		flush()
+
+		transcript.Segments = append(transcript.Segments, seg)
+		text = append(text, segText)
+	}
+	transcript.Text = strings.Join(text, " ")
+
+	return transcript, nil
+}
+
+func mean(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	sum := 0.0
+	for _, v := range values {
+		sum += v
+	}
+	return sum / float64(len(values))
+}
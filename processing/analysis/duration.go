@@ -0,0 +1,110 @@
+package analysis
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ApproxTolerance is how far a "~45s" spec lets clips stray from the target
+const ApproxTolerance = 0.2
+
+// DurationRange bounds the length of generated clips
+type DurationRange struct {
+	Min    time.Duration
+	Target time.Duration
+	Max    time.Duration
+}
+
+// ParseDurationSpec parses a clip length spec:
+//
+//	30s      exactly 30 seconds
+//	15s-60s  anything from 15 seconds to a minute, aiming for the middle
+//	~45s     about 45 seconds, within ApproxTolerance either way
+func ParseDurationSpec(spec string) (DurationRange, error) {
+	spec = strings.TrimSpace(spec)
+	if spec == "" {
+		return DurationRange{}, fmt.Errorf("duration must not be empty (e.g. 30s, 15s-60s, ~45s)")
+	}
+
+	var r DurationRange
+	switch {
+	case strings.HasPrefix(spec, "~"):
+		target, err := parsePositiveDuration(spec[1:])
+		if err != nil {
+			return DurationRange{}, err
+		}
+		slack := time.Duration(float64(target) * ApproxTolerance)
+		r = DurationRange{Min: target - slack, Target: target, Max: target + slack}
+
+	case strings.Contains(spec, "-"):
+		lo, hi, _ := strings.Cut(spec, "-")
+		min, err := parsePositiveDuration(lo)
+		if err != nil {
+			return DurationRange{}, err
+		}
+		max, err := parsePositiveDuration(hi)
+		if err != nil {
+			return DurationRange{}, err
+		}
+		if min > max {
+			return DurationRange{}, fmt.Errorf("invalid duration %q: minimum %s is longer than maximum %s", spec, min, max)
+		}
+		r = DurationRange{Min: min, Target: min + (max-min)/2, Max: max}
+
+	default:
+		target, err := parsePositiveDuration(spec)
+		if err != nil {
+			return DurationRange{}, err
+		}
+		r = DurationRange{Min: target, Target: target, Max: target}
+	}
+
+	return r, nil
+}
+
+func parsePositiveDuration(s string) (time.Duration, error) {
+	s = strings.TrimSpace(s)
+	d, err := time.ParseDuration(s)
+	if err != nil || d <= 0 {
+		return 0, fmt.Errorf("invalid duration %q (e.g. 30s, 15s-60s, ~45s)", s)
+	}
+	return d, nil
+}
+
+// Fixed reports whether clips must be exactly the target length
+func (r DurationRange) Fixed() bool {
+	return r.Min == r.Max
+}
+
+// String formats the range the way ParseDurationSpec accepts it
+func (r DurationRange) String() string {
+	if r.Fixed() {
+		return r.Target.String()
+	}
+	return fmt.Sprintf("%s-%s (target %s)", r.Min, r.Max, r.Target)
+}
+
+// Fit checks the range against the source length, clamping the target and
+// maximum to it. It fails when even the shortest allowed clip can't be cut.
+func (r DurationRange) Fit(total time.Duration) (DurationRange, error) {
+	if total <= 0 {
+		return r, nil
+	}
+	if r.Min > total {
+		return DurationRange{}, fmt.Errorf("source is only %s long, shorter than the minimum clip length %s", total.Round(time.Millisecond), r.Min)
+	}
+
+	if r.Max > total {
+		r.Max = total
+	}
+	if r.Target > total {
+		r.Target = total
+	}
+	return r, nil
+}
+
+// Contains reports whether d is an acceptable clip length
+func (r DurationRange) Contains(d time.Duration) bool {
+	return d >= r.Min && d <= r.Max
+}
@@ -0,0 +1,44 @@
+package analysis
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseDurationSpec(t *testing.T) {
+	const s = time.Second
+
+	tests := []struct {
+		spec    string
+		want    DurationRange
+		wantErr bool
+	}{
+		{spec: "30s", want: DurationRange{Min: 30 * s, Target: 30 * s, Max: 30 * s}},
+		{spec: " 45s ", want: DurationRange{Min: 45 * s, Target: 45 * s, Max: 45 * s}},
+		{spec: "15s-60s", want: DurationRange{Min: 15 * s, Target: 37500 * time.Millisecond, Max: 60 * s}},
+		{spec: "1m-1m", want: DurationRange{Min: 60 * s, Target: 60 * s, Max: 60 * s}},
+		{spec: "15s - 30s", want: DurationRange{Min: 15 * s, Target: 22500 * time.Millisecond, Max: 30 * s}},
+		{spec: "~45s", want: DurationRange{Min: 36 * s, Target: 45 * s, Max: 54 * s}},
+		{spec: "", wantErr: true},
+		{spec: "abc", wantErr: true},
+		{spec: "30", wantErr: true},
+		{spec: "0s", wantErr: true},
+		{spec: "-5s", wantErr: true},
+		{spec: "60s-15s", wantErr: true},
+		{spec: "15s-", wantErr: true},
+		{spec: "~", wantErr: true},
+		{spec: "~0s", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.spec, func(t *testing.T) {
+			got, err := ParseDurationSpec(tt.spec)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseDurationSpec(%q) error = %v, wantErr %v", tt.spec, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseDurationSpec(%q) = %+v, want %+v", tt.spec, got, tt.want)
+			}
+		})
+	}
+}
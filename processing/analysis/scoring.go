@@ -0,0 +1,243 @@
+package analysis
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+
+	"ai-video-editor/processing/ai"
+)
+
+// Candidate is a stretch of the source that could become a clip
+type Candidate struct {
+	Start  time.Duration
+	End    time.Duration
+	Score  float64
+	Text   string
+	Reason string
+}
+
+// Duration returns the candidate's length
+func (c Candidate) Duration() time.Duration {
+	return c.End - c.Start
+}
+
+// stopWords are dropped from prompts before matching
+var stopWords = map[string]bool{
+	"a": true, "an": true, "the": true, "and": true, "or": true, "of": true,
+	"to": true, "in": true, "on": true, "for": true, "with": true, "find": true,
+	"get": true, "show": true, "me": true, "all": true, "any": true, "some": true,
+	"moments": true, "moment": true, "parts": true, "part": true, "clips": true,
+	"clip": true, "where": true, "when": true, "that": true, "about": true,
+}
+
+// themes expand common prompt intents into transcript cues
+var themes = map[string][]string{
+	"funny":       {"haha", "lol", "laugh", "laughing", "joke", "kidding", "hilarious", "funny", "crazy", "ridiculous", "wait what"},
+	"educational": {"because", "means", "important", "remember", "example", "learn", "key", "reason", "how to", "step", "first", "understand"},
+	"highlights":  {"amazing", "best", "incredible", "wow", "favorite", "love", "huge", "biggest", "finally"},
+	"quotes":      {"always", "never", "believe", "truth", "life", "everyone", "nobody", "think"},
+	"emotional":   {"feel", "felt", "cry", "heart", "love", "miss", "scared", "proud", "sorry"},
+}
+
+// themeAliases map prompt words onto a theme
+var themeAliases = map[string]string{
+	"funny": "funny", "hilarious": "funny", "humor": "funny", "humorous": "funny", "jokes": "funny", "comedy": "funny",
+	"educational": "educational", "learning": "educational", "lesson": "educational", "lessons": "educational", "teach": "educational", "points": "educational",
+	"highlights": "highlights", "highlight": "highlights", "best": "highlights", "exciting": "highlights", "hype": "highlights",
+	"quotes": "quotes", "quote": "quotes", "quotable": "quotes", "insightful": "quotes",
+	"emotional": "emotional", "touching": "emotional", "sad": "emotional", "heartfelt": "emotional",
+}
+
+// Criteria is a prompt parsed into things to look for in the transcript
+type Criteria struct {
+	Keywords []string
+	Cues     []string
+}
+
+// ParsePrompt turns a free-form prompt into scoring criteria
+func ParsePrompt(prompt string) Criteria {
+	var c Criteria
+	seenThemes := map[string]bool{}
+	for _, word := range tokenize(prompt) {
+		if stopWords[word] {
+			continue
+		}
+		c.Keywords = append(c.Keywords, word)
+		if theme, ok := themeAliases[word]; ok && !seenThemes[theme] {
+			seenThemes[theme] = true
+			c.Cues = append(c.Cues, themes[theme]...)
+		}
+	}
+	return c
+}
+
+// ScoreTranscript slides a window of roughly window length across the
+// transcript, aligned to segment boundaries so candidates start and end on
+// natural pauses, and scores each window against the prompt
+func ScoreTranscript(t ai.Transcript, prompt string, window time.Duration) []Candidate {
+	criteria := ParsePrompt(prompt)
+	segments := t.Segments
+	if window <= 0 {
+		window = 30 * time.Second
+	}
+
+	var candidates []Candidate
+	for i := range segments {
+		start := segments[i].Start
+		var texts []string
+		var confidence []float64
+		j := i
+		for ; j < len(segments); j++ {
+			texts = append(texts, segments[j].Text)
+			for _, w := range segments[j].Words {
+				if w.Confidence > 0 {
+					confidence = append(confidence, w.Confidence)
+				}
+			}
+			if segments[j].End-start >= window {
+				break
+			}
+		}
+		if j == len(segments) {
+			j = len(segments) - 1
+		}
+
+		text := strings.Join(texts, " ")
+		score, reason := scoreText(text, criteria)
+
+		// Low-confidence speech is often music or crosstalk; prefer clear audio
+		if len(confidence) > 0 {
+			score *= 0.5 + 0.5*average(confidence)
+		}
+
+		candidates = append(candidates, Candidate{
+			Start:  start,
+			End:    segments[j].End,
+			Score:  score,
+			Text:   text,
+			Reason: reason,
+		})
+	}
+
+	sort.SliceStable(candidates, func(a, b int) bool { return candidates[a].Score > candidates[b].Score })
+	return candidates
+}
+
+// EvenCandidates splits a source with no transcript into equal windows, for
+// video-only processing
+func EvenCandidates(total, window time.Duration) []Candidate {
+	if window <= 0 || total <= 0 {
+		return nil
+	}
+	var candidates []Candidate
+	for start := time.Duration(0); start+window <= total; start += window {
+		candidates = append(candidates, Candidate{
+			Start:  start,
+			End:    start + window,
+			Reason: "evenly spaced (no transcript)",
+		})
+	}
+	return candidates
+}
+
+// SceneCandidates starts a window at the opening and at every scene change of
+// a source with no transcript, so clips begin on a fresh shot. Windows with
+// more cuts score higher, favouring fast-paced stretches.
+func SceneCandidates(cuts []time.Duration, total, window time.Duration) []Candidate {
+	if window <= 0 || total < window {
+		return nil
+	}
+
+	starts := append([]time.Duration{0}, cuts...)
+	var candidates []Candidate
+	for i, start := range starts {
+		// A scene that starts too late still gets a window ending at the end
+		start = min(start, total-window)
+		end := start + window
+
+		n := 0
+		for _, cut := range cuts {
+			if cut > start && cut < end {
+				n++
+			}
+		}
+
+		reason := "starts on a scene change"
+		if i == 0 {
+			reason = "opening shot"
+		}
+		candidates = append(candidates, Candidate{
+			Start:  start,
+			End:    end,
+			Score:  float64(n),
+			Reason: fmt.Sprintf("%s, %d cut(s) (no transcript)", reason, n),
+		})
+	}
+
+	sort.SliceStable(candidates, func(a, b int) bool { return candidates[a].Score > candidates[b].Score })
+	return candidates
+}
+
+func scoreText(text string, c Criteria) (float64, string) {
+	lower := strings.ToLower(text)
+	words := tokenize(text)
+	if len(words) == 0 {
+		return 0, "no speech"
+	}
+
+	counts := map[string]int{}
+	for _, w := range words {
+		counts[w]++
+	}
+
+	var matched []string
+	hits := 0.0
+	for _, k := range c.Keywords {
+		if n := counts[k]; n > 0 {
+			hits += 2 * float64(n)
+			matched = append(matched, k)
+		}
+	}
+	for _, cue := range c.Cues {
+		if n := strings.Count(lower, cue); n > 0 {
+			hits += float64(n)
+			matched = append(matched, cue)
+		}
+	}
+
+	// Normalise by length so long windows don't win just by being long
+	score := hits / float64(len(words)) * 100
+	if len(matched) == 0 {
+		return score, "no prompt matches"
+	}
+	return score, "matched: " + strings.Join(unique(matched), ", ")
+}
+
+func tokenize(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r) && r != '\''
+	})
+}
+
+func unique(values []string) []string {
+	seen := map[string]bool{}
+	var out []string
+	for _, v := range values {
+		if !seen[v] {
+			seen[v] = true
+			out = append(out, v)
+		}
+	}
+	return out
+}
+
+func average(values []float64) float64 {
+	sum := 0.0
+	for _, v := range values {
+		sum += v
+	}
+	return sum / float64(len(values))
+}
@@ -0,0 +1,48 @@
+package analysis
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestSceneCandidates(t *testing.T) {
+	const s = time.Second
+
+	type window struct {
+		start time.Duration
+		score float64
+	}
+	tests := []struct {
+		name          string
+		cuts          []time.Duration
+		total, window time.Duration
+		want          []window
+	}{
+		{name: "no window", cuts: []time.Duration{10 * s}, total: 60 * s, window: 0, want: nil},
+		{name: "source shorter than a window", cuts: nil, total: 10 * s, window: 20 * s, want: nil},
+		{name: "no cuts", cuts: nil, total: 60 * s, window: 20 * s, want: []window{{0, 0}}},
+		{
+			name:   "busiest window first, late scenes clamped to the end",
+			cuts:   []time.Duration{10 * s, 12 * s, 50 * s},
+			total:  60 * s,
+			window: 20 * s,
+			want:   []window{{0, 2}, {10 * s, 1}, {40 * s, 1}, {12 * s, 0}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []window
+			for _, c := range SceneCandidates(tt.cuts, tt.total, tt.window) {
+				if c.Duration() != tt.window {
+					t.Errorf("candidate at %s lasts %s, want %s", c.Start, c.Duration(), tt.window)
+				}
+				got = append(got, window{c.Start, c.Score})
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("SceneCandidates() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,71 @@
+package analysis
+
+import (
+	"sort"
+	"time"
+)
+
+// SelectOptions controls which candidates become clips
+type SelectOptions struct {
+	MaxClips int
+	Length   DurationRange
+}
+
+// Select picks the highest scoring candidates that don't overlap, trimmed or
+// extended into the allowed length range, and returns them in source order
+func Select(candidates []Candidate, total time.Duration, opts SelectOptions) []Candidate {
+	sorted := append([]Candidate(nil), candidates...)
+	sort.SliceStable(sorted, func(a, b int) bool { return sorted[a].Score > sorted[b].Score })
+
+	var selected []Candidate
+	for _, c := range sorted {
+		if opts.MaxClips > 0 && len(selected) >= opts.MaxClips {
+			break
+		}
+
+		c = fitToRange(c, total, opts.Length)
+		if overlapsAny(c, selected) {
+			continue
+		}
+		selected = append(selected, c)
+	}
+
+	sort.Slice(selected, func(a, b int) bool { return selected[a].Start < selected[b].Start })
+	return selected
+}
+
+// fitToRange keeps candidates whose length is already allowed, so they stay
+// on natural pause boundaries, and otherwise centres them on a window of the
+// target length, clamped to the source
+func fitToRange(c Candidate, total time.Duration, length DurationRange) Candidate {
+	target := length.Target
+	if target <= 0 || length.Contains(c.Duration()) {
+		return c
+	}
+
+	mid := c.Start + c.Duration()/2
+	c.Start = mid - target/2
+	c.End = c.Start + target
+
+	if c.Start < 0 {
+		c.End -= c.Start
+		c.Start = 0
+	}
+	if total > 0 && c.End > total {
+		c.Start -= c.End - total
+		c.End = total
+		if c.Start < 0 {
+			c.Start = 0
+		}
+	}
+	return c
+}
+
+func overlapsAny(c Candidate, selected []Candidate) bool {
+	for _, s := range selected {
+		if c.Start < s.End && s.Start < c.End {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,141 @@
+package captions
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// assPlayResY is the script's reference height. Sizes and margins in a Style
+// are pixels at this height and scale with the real frame.
+const assPlayResY = 1080
+
+// WriteASS writes cues as an Advanced SubStation Alpha script with karaoke
+// highlighting: each word changes from Color to HighlightColor as it is
+// spoken. Cues are split into lines of at most MaxWordsPerLine words, each
+// shown on its own. width and height are the video's display size.
+func WriteASS(w io.Writer, cues []Cue, style Style, width, height int) error {
+	if err := style.Validate(); err != nil {
+		return err
+	}
+
+	playResX := assPlayResY * 16 / 9
+	if width > 0 && height > 0 {
+		playResX = int(math.Round(float64(assPlayResY) * float64(width) / float64(height)))
+	}
+	align, _ := style.alignment()
+	primary, _ := assColor(style.HighlightColor)
+	secondary, _ := assColor(style.Color)
+	outline, _ := assColor(style.OutlineColor)
+	bold := 0
+	if style.Bold {
+		bold = -1
+	}
+
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, "[Script Info]\nScriptType: v4.00+\nPlayResX: %d\nPlayResY: %d\nScaledBorderAndShadow: yes\nWrapStyle: 2\n\n", playResX, assPlayResY)
+
+	// With \k karaoke, SecondaryColour is shown until a syllable's time comes
+	// and PrimaryColour from then on
+	fmt.Fprint(bw, "[V4+ Styles]\nFormat: Name, Fontname, Fontsize, PrimaryColour, SecondaryColour, OutlineColour, BackColour, Bold, Italic, Underline, StrikeOut, ScaleX, ScaleY, Spacing, Angle, BorderStyle, Outline, Shadow, Alignment, MarginL, MarginR, MarginV, Encoding\n")
+	fmt.Fprintf(bw, "Style: Default,%s,%d,%s,%s,%s,&H80000000,%d,0,0,0,100,100,0,0,1,%s,%s,%d,60,60,%d,1\n\n",
+		assText(style.Font), style.Size, primary, secondary, outline, bold,
+		formatFloat(style.Outline), formatFloat(style.Shadow), align, style.Margin)
+
+	fmt.Fprint(bw, "[Events]\nFormat: Layer, Start, End, Style, Name, MarginL, MarginR, MarginV, Effect, Text\n")
+	for _, line := range karaokeLines(cues, style.MaxWordsPerLine) {
+		fmt.Fprintf(bw, "Dialogue: 0,%s,%s,Default,,0,0,0,,%s\n", assTime(line.Start), assTime(line.End), karaokeText(line))
+	}
+	return bw.Flush()
+}
+
+// karaokeLines splits each cue into consecutive lines of at most maxWords
+// words. A line lasts until the next one starts, or the cue ends.
+func karaokeLines(cues []Cue, maxWords int) []Cue {
+	var lines []Cue
+	for _, c := range cues {
+		for i := 0; i < len(c.Words); i += maxWords {
+			words := c.Words[i:min(i+maxWords, len(c.Words))]
+			line := Cue{Start: words[0].Start, End: c.End, Words: words}
+			if i == 0 {
+				line.Start = c.Start
+			}
+			if i+maxWords < len(c.Words) {
+				line.End = c.Words[i+maxWords].Start
+			}
+			line.Lines = []string{strings.Join(wordTexts(words), " ")}
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
+
+// karaokeText tags each word with a \k duration so it is highlighted from
+// the moment it starts being spoken. Durations are rounded from the line's
+// start so rounding never accumulates.
+func karaokeText(line Cue) string {
+	var b strings.Builder
+	elapsed := int64(0)
+	until := func(t time.Duration) int64 {
+		cs := max(centiseconds(t-line.Start)-elapsed, 0)
+		elapsed += cs
+		return cs
+	}
+
+	if line.Words[0].Start > line.Start {
+		fmt.Fprintf(&b, "{\\k%d}", until(line.Words[0].Start))
+	}
+	for i, w := range line.Words {
+		next := line.End
+		if i+1 < len(line.Words) {
+			next = line.Words[i+1].Start
+		}
+		if i > 0 {
+			b.WriteString(" ")
+		}
+		fmt.Fprintf(&b, "{\\k%d}%s", until(next), assText(strings.TrimSpace(w.Text)))
+	}
+	return b.String()
+}
+
+// assColor converts #RRGGBB or #RRGGBBAA to ASS's &HAABBGGRR, where alpha
+// 00 is opaque
+func assColor(hex string) (string, error) {
+	h := strings.TrimPrefix(hex, "#")
+	if len(h) != 6 && len(h) != 8 {
+		return "", fmt.Errorf("color %q is not #RRGGBB or #RRGGBBAA", hex)
+	}
+	if _, err := strconv.ParseUint(h, 16, 32); err != nil {
+		return "", fmt.Errorf("color %q is not #RRGGBB or #RRGGBBAA", hex)
+	}
+
+	alpha := "00"
+	if len(h) == 8 {
+		a, _ := strconv.ParseUint(h[6:], 16, 8)
+		alpha = fmt.Sprintf("%02X", 255-a)
+	}
+	return strings.ToUpper("&H" + alpha + h[4:6] + h[2:4] + h[0:2]), nil
+}
+
+// assText keeps text from being read as override tags or line breaks
+func assText(s string) string {
+	return strings.NewReplacer("{", "(", "}", ")", "\\", "/", "\n", " ").Replace(s)
+}
+
+// assTime formats d as H:MM:SS.cc
+func assTime(d time.Duration) string {
+	cs := centiseconds(max(d, 0))
+	return fmt.Sprintf("%d:%02d:%02d.%02d", cs/360000, cs/6000%60, cs/100%60, cs%100)
+}
+
+func centiseconds(d time.Duration) int64 {
+	return int64(math.Round(float64(d) / float64(10*time.Millisecond)))
+}
+
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
@@ -0,0 +1,95 @@
+package captions
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"ai-video-editor/processing/ai"
+)
+
+func TestKaraokeLines(t *testing.T) {
+	words := func(texts ...string) []ai.Word {
+		// Half a second per word, starting at 200ms
+		var ws []ai.Word
+		for i, text := range texts {
+			start := 200*ms + time.Duration(i)*500*ms
+			ws = append(ws, ai.Word{Text: text, Start: start, End: start + 500*ms})
+		}
+		return ws
+	}
+
+	tests := []struct {
+		name     string
+		cues     []Cue
+		maxWords int
+		want     []Cue
+	}{
+		{
+			name:     "cue within the word limit",
+			cues:     []Cue{{Start: 0, End: 2 * time.Second, Words: words("a", "b")}},
+			maxWords: 3,
+			want:     []Cue{{Start: 0, End: 2 * time.Second, Lines: []string{"a b"}, Words: words("a", "b")}},
+		},
+		{
+			name:     "lines last until the next one starts",
+			cues:     []Cue{{Start: 0, End: 3 * time.Second, Words: words("a", "b", "c", "d", "e")}},
+			maxWords: 2,
+			want: []Cue{
+				{Start: 0, End: 1200 * ms, Lines: []string{"a b"}, Words: words("a", "b", "c", "d", "e")[0:2]},
+				{Start: 1200 * ms, End: 2200 * ms, Lines: []string{"c d"}, Words: words("a", "b", "c", "d", "e")[2:4]},
+				{Start: 2200 * ms, End: 3 * time.Second, Lines: []string{"e"}, Words: words("a", "b", "c", "d", "e")[4:]},
+			},
+		},
+		{
+			name: "cues split separately",
+			cues: []Cue{
+				{Start: 0, End: time.Second, Words: words("a")},
+				{Start: 5 * time.Second, End: 6 * time.Second, Words: words("b")},
+			},
+			maxWords: 4,
+			want: []Cue{
+				{Start: 0, End: time.Second, Lines: []string{"a"}, Words: words("a")},
+				{Start: 5 * time.Second, End: 6 * time.Second, Lines: []string{"b"}, Words: words("b")},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := karaokeLines(tt.cues, tt.maxWords); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("karaokeLines() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAssColor(t *testing.T) {
+	tests := []struct {
+		hex     string
+		want    string
+		wantErr bool
+	}{
+		{hex: "#FFFFFF", want: "&H00FFFFFF"},
+		{hex: "#FF0000", want: "&H000000FF"},
+		{hex: "abcdef", want: "&H00EFCDAB"},
+		{hex: "#00FF0080", want: "&H7F00FF00"},
+		{hex: "#12345678", want: "&H87563412"},
+		{hex: "#000000FF", want: "&H00000000"},
+		{hex: "#FFF", wantErr: true},
+		{hex: "#GGGGGG", wantErr: true},
+		{hex: "", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.hex, func(t *testing.T) {
+			got, err := assColor(tt.hex)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("assColor(%q) error = %v, wantErr %v", tt.hex, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("assColor(%q) = %q, want %q", tt.hex, got, tt.want)
+			}
+		})
+	}
+}
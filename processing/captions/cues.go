@@ -0,0 +1,235 @@
+package captions
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"ai-video-editor/processing/ai"
+)
+
+// Defaults follow common broadcast subtitle guidelines
+const (
+	DefaultMaxLineLength = 42
+	DefaultMaxLines      = 2
+	DefaultMaxCPS        = 17
+	DefaultMinDuration   = time.Second
+	DefaultMaxDuration   = 7 * time.Second
+
+	// A pause this long between words always starts a new cue
+	cueBreakGap = 800 * time.Millisecond
+
+	// How far a cue may be held back so the one before it can be read
+	maxCueDelay = 500 * time.Millisecond
+)
+
+// Options limits how much text a cue holds and how fast it must be read
+type Options struct {
+	MaxLineLength int           `json:"max_line_length"` // Characters per line
+	MaxLines      int           `json:"max_lines"`       // Lines per cue
+	MaxCPS        float64       `json:"max_cps"`         // Reading speed in characters per second
+	MinDuration   time.Duration `json:"min_duration"`
+	MaxDuration   time.Duration `json:"max_duration"`
+}
+
+// DefaultOptions returns the default caption limits
+func DefaultOptions() Options {
+	return Options{
+		MaxLineLength: DefaultMaxLineLength,
+		MaxLines:      DefaultMaxLines,
+		MaxCPS:        DefaultMaxCPS,
+		MinDuration:   DefaultMinDuration,
+		MaxDuration:   DefaultMaxDuration,
+	}
+}
+
+// Validate rejects limits no cue could satisfy
+func (o Options) Validate() error {
+	if o.MaxLineLength < 10 {
+		return fmt.Errorf("caption line length %d is too short (minimum 10)", o.MaxLineLength)
+	}
+	if o.MaxLines < 1 {
+		return fmt.Errorf("captions need at least one line per cue, got %d", o.MaxLines)
+	}
+	if o.MaxCPS <= 0 {
+		return fmt.Errorf("caption reading speed must be positive, got %g", o.MaxCPS)
+	}
+	if o.MaxDuration > 0 && o.MinDuration > o.MaxDuration {
+		return fmt.Errorf("caption minimum duration %s is longer than the maximum %s", o.MinDuration, o.MaxDuration)
+	}
+	return nil
+}
+
+// Cue is one caption on screen. Times are relative to the start of the clip.
+type Cue struct {
+	Start time.Duration
+	End   time.Duration
+	Lines []string
+	Words []ai.Word // The words shown, for word-level highlighting
+}
+
+// Text returns the cue's lines joined by spaces
+func (c Cue) Text() string {
+	return strings.Join(c.Lines, " ")
+}
+
+// BuildCues turns the part of a transcript between start and end into cues
+// timed from the clip's zero point. Words are grouped until a line, cue length
+// or pause limit is reached, then each cue is held on screen long enough to be
+// read at MaxCPS as far as the following cues allow.
+func BuildCues(t ai.Transcript, start, end time.Duration, opts Options) []Cue {
+	length := end - start
+	words := timedWords(t.Slice(start, end).Shift(-start))
+
+	var cues []Cue
+	var current []ai.Word
+	flush := func() {
+		if len(current) > 0 {
+			cues = append(cues, newCue(current, opts))
+			current = nil
+		}
+	}
+
+	for _, w := range words {
+		w.Start = clamp(w.Start, 0, length)
+		w.End = clamp(w.End, w.Start, length)
+
+		if len(current) > 0 {
+			last := current[len(current)-1]
+			candidate := append(current[:len(current):len(current)], w)
+			switch {
+			case w.Start-last.End >= cueBreakGap,
+				opts.MaxDuration > 0 && w.End-current[0].Start > opts.MaxDuration,
+				len(wrap(wordTexts(candidate), opts.MaxLineLength)) > opts.MaxLines:
+				flush()
+			}
+		}
+		current = append(current, w)
+
+		// End cues at sentence boundaries so each one reads as a unit
+		if endsSentence(w.Text) {
+			flush()
+		}
+	}
+	flush()
+
+	retime(cues, length, opts)
+	return cues
+}
+
+func newCue(words []ai.Word, opts Options) Cue {
+	return Cue{
+		Start: words[0].Start,
+		End:   words[len(words)-1].End,
+		Lines: wrap(wordTexts(words), opts.MaxLineLength),
+		Words: words,
+	}
+}
+
+// retime extends cues that are too short to read. When speech is faster than
+// MaxCPS the next cue is held back by up to maxCueDelay to make room; cues
+// never overlap or run past the end of the clip.
+func retime(cues []Cue, length time.Duration, opts Options) {
+	for i := range cues {
+		c := &cues[i]
+
+		need := time.Duration(float64(utf8.RuneCountInString(c.Text())) / opts.MaxCPS * float64(time.Second))
+		need = max(need, opts.MinDuration)
+		if opts.MaxDuration > 0 {
+			need = min(need, opts.MaxDuration)
+		}
+		if c.End-c.Start >= need {
+			continue
+		}
+
+		end := min(c.Start+need, length)
+		if i+1 < len(cues) {
+			next := &cues[i+1]
+			latest := min(next.Start+maxCueDelay, next.End-opts.MinDuration)
+			end = min(end, max(latest, next.Start))
+			next.Start = max(next.Start, end)
+		}
+		c.End = max(c.End, end)
+	}
+}
+
+// timedWords returns the transcript's words, estimating word timings from
+// segment timings for backends that don't report them
+func timedWords(t ai.Transcript) []ai.Word {
+	var words []ai.Word
+	for _, s := range t.Segments {
+		if len(s.Words) > 0 {
+			words = append(words, s.Words...)
+			continue
+		}
+		words = append(words, spreadWords(s)...)
+	}
+	return words
+}
+
+// spreadWords splits a segment's text into words sharing its time span in
+// proportion to their length
+func spreadWords(s ai.Segment) []ai.Word {
+	fields := strings.Fields(s.Text)
+	if len(fields) == 0 {
+		return nil
+	}
+
+	total := 0
+	for _, f := range fields {
+		total += utf8.RuneCountInString(f)
+	}
+
+	span := s.End - s.Start
+	words := make([]ai.Word, len(fields))
+	pos, done := s.Start, 0
+	for i, f := range fields {
+		done += utf8.RuneCountInString(f)
+		end := s.Start + time.Duration(float64(span)*float64(done)/float64(total))
+		words[i] = ai.Word{Text: f, Start: pos, End: end}
+		pos = end
+	}
+	return words
+}
+
+// wrap breaks words into lines of at most width characters. A word longer
+// than width gets a line to itself.
+func wrap(words []string, width int) []string {
+	var lines []string
+	line := ""
+	for _, w := range words {
+		switch {
+		case line == "":
+			line = w
+		case utf8.RuneCountInString(line)+1+utf8.RuneCountInString(w) <= width:
+			line += " " + w
+		default:
+			lines = append(lines, line)
+			line = w
+		}
+	}
+	if line != "" {
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+func wordTexts(words []ai.Word) []string {
+	texts := make([]string, 0, len(words))
+	for _, w := range words {
+		if t := strings.TrimSpace(w.Text); t != "" {
+			texts = append(texts, t)
+		}
+	}
+	return texts
+}
+
+func endsSentence(word string) bool {
+	word = strings.TrimRight(strings.TrimSpace(word), `"')]”’`)
+	return strings.HasSuffix(word, ".") || strings.HasSuffix(word, "?") || strings.HasSuffix(word, "!")
+}
+
+func clamp(d, lo, hi time.Duration) time.Duration {
+	return max(lo, min(d, hi))
+}
@@ -0,0 +1,183 @@
+package captions
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"ai-video-editor/processing/ai"
+)
+
+const ms = time.Millisecond
+
+func TestWrap(t *testing.T) {
+	tests := []struct {
+		name  string
+		words []string
+		width int
+		want  []string
+	}{
+		{"empty", nil, 10, nil},
+		{"fits on one line", []string{"ab", "cd"}, 5, []string{"ab cd"}},
+		{"breaks between words", []string{"one", "two", "three"}, 7, []string{"one two", "three"}},
+		{"long word gets its own line", []string{"a", "extraordinarily", "b"}, 5, []string{"a", "extraordinarily", "b"}},
+		{"counts runes not bytes", []string{"héllo", "wörld"}, 11, []string{"héllo wörld"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := wrap(tt.words, tt.width); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("wrap(%q, %d) = %q, want %q", tt.words, tt.width, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRetime(t *testing.T) {
+	opts := Options{MaxLineLength: 42, MaxLines: 2, MaxCPS: 10, MinDuration: time.Second, MaxDuration: 5 * time.Second}
+	cue := func(start, end time.Duration, text string) Cue {
+		return Cue{Start: start, End: end, Lines: []string{text}}
+	}
+
+	tests := []struct {
+		name   string
+		cues   []Cue
+		length time.Duration
+		want   [][2]time.Duration
+	}{
+		{
+			name:   "short cue extended to the minimum",
+			cues:   []Cue{cue(0, 200*ms, "hello")},
+			length: 10 * time.Second,
+			want:   [][2]time.Duration{{0, time.Second}},
+		},
+		{
+			name:   "long enough cue untouched",
+			cues:   []Cue{cue(0, 2*time.Second, "hi")},
+			length: 10 * time.Second,
+			want:   [][2]time.Duration{{0, 2 * time.Second}},
+		},
+		{
+			name:   "never past the end of the clip",
+			cues:   []Cue{cue(9500*ms, 9700*ms, "hello")},
+			length: 10 * time.Second,
+			want:   [][2]time.Duration{{9500 * ms, 10 * time.Second}},
+		},
+		{
+			name:   "reading time capped at the maximum",
+			cues:   []Cue{cue(0, time.Second, "this caption has far too many characters to read in five seconds at ten per second")},
+			length: 20 * time.Second,
+			want:   [][2]time.Duration{{0, 5 * time.Second}},
+		},
+		{
+			name:   "next cue held back",
+			cues:   []Cue{cue(0, 300*ms, "hello"), cue(500*ms, 3*time.Second, "world")},
+			length: 10 * time.Second,
+			want:   [][2]time.Duration{{0, time.Second}, {time.Second, 3 * time.Second}},
+		},
+		{
+			name:   "next cue held back by at most the delay",
+			cues:   []Cue{cue(0, 100*ms, "a long sentence here"), cue(200*ms, 5*time.Second, "world")},
+			length: 10 * time.Second,
+			want:   [][2]time.Duration{{0, 700 * ms}, {700 * ms, 5 * time.Second}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			retime(tt.cues, tt.length, opts)
+			if got := cueTimes(tt.cues); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("retime() times = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBuildCues(t *testing.T) {
+	word := func(text string, start, end time.Duration) ai.Word {
+		return ai.Word{Text: text, Start: start, End: end}
+	}
+	transcript := func(words ...ai.Word) ai.Transcript {
+		return ai.Transcript{Segments: []ai.Segment{{Start: words[0].Start, End: words[len(words)-1].End, Words: words}}}
+	}
+	narrow := DefaultOptions()
+	narrow.MaxLineLength, narrow.MaxLines = 10, 1
+
+	tests := []struct {
+		name       string
+		transcript ai.Transcript
+		start, end time.Duration
+		opts       Options
+		wantTimes  [][2]time.Duration
+		wantLines  [][]string
+	}{
+		{
+			name: "sentences end cues",
+			transcript: transcript(
+				word("Hello", 0, 400*ms), word("there.", 400*ms, 800*ms),
+				word("How", time.Second, 1200*ms), word("are", 1200*ms, 1400*ms), word("you?", 1400*ms, 1800*ms),
+			),
+			end:       10 * time.Second,
+			opts:      DefaultOptions(),
+			wantTimes: [][2]time.Duration{{0, time.Second}, {time.Second, 2 * time.Second}},
+			wantLines: [][]string{{"Hello there."}, {"How are you?"}},
+		},
+		{
+			name:       "a pause starts a new cue",
+			transcript: transcript(word("one", 0, 300*ms), word("two", 1500*ms, 1800*ms)),
+			end:        10 * time.Second,
+			opts:       DefaultOptions(),
+			wantTimes:  [][2]time.Duration{{0, time.Second}, {1500 * ms, 2500 * ms}},
+			wantLines:  [][]string{{"one"}, {"two"}},
+		},
+		{
+			name:       "line limit starts a new cue",
+			transcript: transcript(word("aaaa", 0, 500*ms), word("bbbb", 500*ms, time.Second), word("cccc", time.Second, 1500*ms)),
+			end:        10 * time.Second,
+			opts:       narrow,
+			wantTimes:  [][2]time.Duration{{0, time.Second}, {time.Second, 2 * time.Second}},
+			wantLines:  [][]string{{"aaaa bbbb"}, {"cccc"}},
+		},
+		{
+			name:       "timed from the clip start",
+			transcript: transcript(word("Before.", 8*time.Second, 9*time.Second), word("Hi.", 10*time.Second, 10500*ms)),
+			start:      10 * time.Second,
+			end:        20 * time.Second,
+			opts:       DefaultOptions(),
+			wantTimes:  [][2]time.Duration{{0, time.Second}},
+			wantLines:  [][]string{{"Hi."}},
+		},
+		{
+			name:       "word timings estimated from the segment",
+			transcript: ai.Transcript{Segments: []ai.Segment{{Start: 0, End: 2 * time.Second, Text: "ab cd"}}},
+			end:        10 * time.Second,
+			opts:       DefaultOptions(),
+			wantTimes:  [][2]time.Duration{{0, 2 * time.Second}},
+			wantLines:  [][]string{{"ab cd"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cues := BuildCues(tt.transcript, tt.start, tt.end, tt.opts)
+			if got := cueTimes(cues); !reflect.DeepEqual(got, tt.wantTimes) {
+				t.Errorf("BuildCues() times = %v, want %v", got, tt.wantTimes)
+			}
+			var lines [][]string
+			for _, c := range cues {
+				lines = append(lines, c.Lines)
+			}
+			if !reflect.DeepEqual(lines, tt.wantLines) {
+				t.Errorf("BuildCues() lines = %q, want %q", lines, tt.wantLines)
+			}
+		})
+	}
+}
+
+func cueTimes(cues []Cue) [][2]time.Duration {
+	var times [][2]time.Duration
+	for _, c := range cues {
+		times = append(times, [2]time.Duration{c.Start, c.End})
+	}
+	return times
+}
@@ -0,0 +1,190 @@
+package captions
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/spf13/viper"
+)
+
+// Positions accepted by Style.Position
+const (
+	PositionBottom = "bottom"
+	PositionMiddle = "middle"
+	PositionTop    = "top"
+)
+
+// CaptionStylesKey is the config section holding caption style presets, e.g.
+//
+//	caption-styles:
+//	  lower-third:
+//	    font: Helvetica
+//	  brand:
+//	    highlight-color: "#FF0050"
+const CaptionStylesKey = "caption-styles"
+
+// DefaultStyleName is the style used when none is given
+const DefaultStyleName = "bold-center"
+
+// Style controls how burned-in captions look. Colors are #RRGGBB, or
+// #RRGGBBAA with alpha 00 fully transparent. The mapstructure tags are the
+// field names used in the caption-styles config.
+type Style struct {
+	Font            string  `mapstructure:"font" json:"font"`
+	Size            int     `mapstructure:"size" json:"size"` // Font size in pixels at 1080p
+	Bold            bool    `mapstructure:"bold" json:"bold"`
+	Color           string  `mapstructure:"color" json:"color"`                     // Words not yet spoken
+	HighlightColor  string  `mapstructure:"highlight-color" json:"highlight_color"` // Words already spoken
+	OutlineColor    string  `mapstructure:"outline-color" json:"outline_color"`
+	Outline         float64 `mapstructure:"outline" json:"outline"` // Outline width in pixels at 1080p
+	Shadow          float64 `mapstructure:"shadow" json:"shadow"`
+	Position        string  `mapstructure:"position" json:"position"` // bottom, middle or top
+	Margin          int     `mapstructure:"margin" json:"margin"`     // Distance from the top or bottom edge in pixels at 1080p
+	MaxWordsPerLine int     `mapstructure:"max-words" json:"max_words"`
+}
+
+// StylePresets are the built-in caption styles for --caption-style
+var StylePresets = map[string]Style{
+	// Big words in the middle of a vertical frame, a few at a time
+	"bold-center": {
+		Font:            "Arial",
+		Size:            80,
+		Bold:            true,
+		Color:           "#FFFFFF",
+		HighlightColor:  "#FFD700",
+		OutlineColor:    "#000000",
+		Outline:         5,
+		Position:        PositionMiddle,
+		MaxWordsPerLine: 3,
+	},
+	// A broadcast-style line near the bottom edge
+	"lower-third": {
+		Font:            "Arial",
+		Size:            56,
+		Bold:            true,
+		Color:           "#FFFFFF",
+		HighlightColor:  "#4FC3F7",
+		OutlineColor:    "#000000",
+		Outline:         3,
+		Shadow:          1,
+		Position:        PositionBottom,
+		Margin:          140,
+		MaxWordsPerLine: 6,
+	},
+	// Small, unobtrusive captions with a subtle highlight
+	"minimal": {
+		Font:            "Arial",
+		Size:            44,
+		Color:           "#D0D0D0",
+		HighlightColor:  "#FFFFFF",
+		OutlineColor:    "#000000",
+		Outline:         2,
+		Position:        PositionBottom,
+		Margin:          80,
+		MaxWordsPerLine: 8,
+	},
+}
+
+// StyleFields are the keys a caption style can set in the config file
+var StyleFields = []string{
+	"font", "size", "bold", "color", "highlight-color", "outline-color",
+	"outline", "shadow", "position", "margin", "max-words",
+}
+
+// DefaultStyle returns the built-in default caption style
+func DefaultStyle() Style {
+	return StylePresets[DefaultStyleName]
+}
+
+// StyleNames lists the built-in styles plus any defined only in the config file
+func StyleNames() []string {
+	seen := map[string]bool{}
+	for name := range StylePresets {
+		seen[name] = true
+	}
+	for name := range viper.GetStringMap(CaptionStylesKey) {
+		seen[name] = true
+	}
+
+	names := make([]string, 0, len(seen))
+	for name := range seen {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// StylePreset returns the caption style for a name with any fields from the
+// config file applied. Styles that exist only in the config file start from
+// the default style.
+func StylePreset(name string) (Style, error) {
+	style, builtin := StylePresets[name]
+	overridden := viper.IsSet(CaptionStylesKey + "." + name)
+	if !builtin && !overridden {
+		return Style{}, fmt.Errorf("unknown caption style %q (use %s)", name, strings.Join(StyleNames(), ", "))
+	}
+	if !builtin {
+		style = DefaultStyle()
+	}
+
+	if overridden {
+		if err := viper.UnmarshalKey(CaptionStylesKey+"."+name, &style); err != nil {
+			return Style{}, fmt.Errorf("invalid %s.%s in config: %w", CaptionStylesKey, name, err)
+		}
+	}
+
+	if err := style.Validate(); err != nil {
+		return Style{}, fmt.Errorf("invalid caption style %q: %w", name, err)
+	}
+	return style, nil
+}
+
+// Validate rejects styles libass couldn't render
+func (s Style) Validate() error {
+	if s.Font == "" {
+		return fmt.Errorf("font must not be empty")
+	}
+	if s.Size <= 0 {
+		return fmt.Errorf("size must be positive, got %d", s.Size)
+	}
+	for _, c := range []struct{ field, value string }{
+		{"color", s.Color},
+		{"highlight-color", s.HighlightColor},
+		{"outline-color", s.OutlineColor},
+	} {
+		if _, err := assColor(c.value); err != nil {
+			return fmt.Errorf("%s: %w", c.field, err)
+		}
+	}
+	if s.Outline < 0 {
+		return fmt.Errorf("outline must not be negative, got %g", s.Outline)
+	}
+	if s.Shadow < 0 {
+		return fmt.Errorf("shadow must not be negative, got %g", s.Shadow)
+	}
+	if _, err := s.alignment(); err != nil {
+		return err
+	}
+	if s.Margin < 0 {
+		return fmt.Errorf("margin must not be negative, got %d", s.Margin)
+	}
+	if s.MaxWordsPerLine < 1 {
+		return fmt.Errorf("max-words must be at least 1, got %d", s.MaxWordsPerLine)
+	}
+	return nil
+}
+
+// alignment converts Position to an ASS numpad alignment, centred horizontally
+func (s Style) alignment() (int, error) {
+	switch s.Position {
+	case PositionBottom, "":
+		return 2, nil
+	case PositionMiddle:
+		return 5, nil
+	case PositionTop:
+		return 8, nil
+	default:
+		return 0, fmt.Errorf("position %q is not bottom, middle or top", s.Position)
+	}
+}
@@ -0,0 +1,63 @@
+package captions
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+)
+
+// WriteSRT writes cues in SubRip format
+func WriteSRT(w io.Writer, cues []Cue) error {
+	bw := bufio.NewWriter(w)
+	for i, c := range cues {
+		fmt.Fprintf(bw, "%d\n%s --> %s\n%s\n\n", i+1,
+			timestamp(c.Start, ","), timestamp(c.End, ","), strings.Join(c.Lines, "\n"))
+	}
+	return bw.Flush()
+}
+
+// WriteVTT writes cues in WebVTT format
+func WriteVTT(w io.Writer, cues []Cue) error {
+	bw := bufio.NewWriter(w)
+	fmt.Fprint(bw, "WEBVTT\n\n")
+	for _, c := range cues {
+		// & and < start markup in WebVTT cue text
+		lines := make([]string, len(c.Lines))
+		for i, line := range c.Lines {
+			lines[i] = vttEscaper.Replace(line)
+		}
+		fmt.Fprintf(bw, "%s --> %s\n%s\n\n",
+			timestamp(c.Start, "."), timestamp(c.End, "."), strings.Join(lines, "\n"))
+	}
+	return bw.Flush()
+}
+
+var vttEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;")
+
+// WriteFile writes cues to path with the given writer, e.g. WriteSRT
+func WriteFile(path string, cues []Cue, write func(io.Writer, []Cue) error) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create caption file: %w", err)
+	}
+	if err := write(f, cues); err != nil {
+		f.Close()
+		return fmt.Errorf("failed to write caption file: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("failed to write caption file: %w", err)
+	}
+	return nil
+}
+
+// timestamp formats d as HH:MM:SS followed by sep and milliseconds
+func timestamp(d time.Duration, sep string) string {
+	if d < 0 {
+		d = 0
+	}
+	ms := d.Milliseconds()
+	return fmt.Sprintf("%02d:%02d:%02d%s%03d", ms/3600000, ms/60000%60, ms/1000%60, sep, ms%1000)
+}
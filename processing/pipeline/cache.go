@@ -0,0 +1,101 @@
+package pipeline
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"ai-video-editor/processing/ai"
+	"ai-video-editor/processing/store"
+	"ai-video-editor/processing/video"
+)
+
+// CacheVersion is part of every cache key. Bump it whenever a change to the
+// analysis stages would make previously cached results wrong.
+const CacheVersion = 2
+
+// CacheKey identifies the analysis settings a cached result was produced
+// with, alongside the source's content hash
+func CacheKey(model, language string, chunks video.ChunkOptions, sceneThreshold float64) string {
+	if language == "" {
+		language = "auto"
+	}
+	return fmt.Sprintf("%s|lang=%s|chunks=%s/%s|scenes=%g|v%d", model, language, chunks.Length, chunks.Overlap, sceneThreshold, CacheVersion)
+}
+
+// contentAnalysis is the JSON stored in the content_analysis column
+type contentAnalysis struct {
+	Probe  video.MediaInfo         `json:"probe"`
+	Frames []video.FrameDescriptor `json:"frames,omitempty"`
+}
+
+// loadCachedAnalysis fills in the job's probe, transcript, scenes and frame
+// descriptors from the cache. It reports false on a miss.
+func loadCachedAnalysis(ctx context.Context, job *Job) (bool, error) {
+	entry, err := job.Cache.LookupAnalysis(ctx, job.ContentHash, job.Options.CacheKey)
+	if errors.Is(err, store.ErrNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	var analysis contentAnalysis
+	var transcript ai.Transcript
+	var scenes []time.Duration
+	if err := json.Unmarshal([]byte(entry.ContentAnalysis), &analysis); err != nil {
+		return false, nil // Unreadable entries are treated as a miss and overwritten
+	}
+	if entry.Transcript != "" {
+		if err := json.Unmarshal([]byte(entry.Transcript), &transcript); err != nil {
+			return false, nil
+		}
+	}
+	if entry.SceneBoundaries != "" {
+		if err := json.Unmarshal([]byte(entry.SceneBoundaries), &scenes); err != nil {
+			return false, nil
+		}
+	}
+
+	// The cached probe names the file as it was first seen
+	analysis.Probe.Filename = job.Input
+
+	job.Media = analysis.Probe
+	job.Transcript = transcript
+	job.Scenes = scenes
+	job.Frames = analysis.Frames
+	return true, nil
+}
+
+// saveAnalysis stores the job's probe, transcript, scenes and frame
+// descriptors in the cache
+func saveAnalysis(ctx context.Context, job *Job) error {
+	if job.Cache == nil || job.FromCache || job.ContentHash == "" {
+		return nil
+	}
+
+	analysis, err := json.Marshal(contentAnalysis{Probe: job.Media, Frames: job.Frames})
+	if err != nil {
+		return fmt.Errorf("failed to encode analysis for cache: %w", err)
+	}
+	transcript, err := json.Marshal(job.Transcript)
+	if err != nil {
+		return fmt.Errorf("failed to encode transcript for cache: %w", err)
+	}
+	scenes, err := json.Marshal(job.Scenes)
+	if err != nil {
+		return fmt.Errorf("failed to encode scenes for cache: %w", err)
+	}
+
+	return job.Cache.SaveAnalysis(ctx, &store.AnalysisEntry{
+		VideoPath:       job.Input,
+		VideoHash:       job.ContentHash,
+		Duration:        job.Media.Duration,
+		Transcript:      string(transcript),
+		SceneBoundaries: string(scenes),
+		ContentAnalysis: string(analysis),
+		AnalysisModel:   job.Options.CacheKey,
+	})
+}
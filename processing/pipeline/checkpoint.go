@@ -0,0 +1,100 @@
+package pipeline
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"ai-video-editor/processing/ai"
+	"ai-video-editor/processing/analysis"
+	"ai-video-editor/processing/store"
+	"ai-video-editor/processing/video"
+)
+
+// Checkpoint is the durable part of a job's state after a stage completes
+type Checkpoint struct {
+	Options     Options                 `json:"options"`
+	ContentHash string                  `json:"content_hash,omitempty"`
+	FromCache   bool                    `json:"from_cache,omitempty"`
+	Media       video.MediaInfo         `json:"media"`
+	Transcript  ai.Transcript           `json:"transcript"`
+	Scenes      []time.Duration         `json:"scenes,omitempty"`
+	Frames      []video.FrameDescriptor `json:"frames,omitempty"`
+	Candidates  []analysis.Candidate    `json:"candidates,omitempty"`
+	Selected    []analysis.Candidate    `json:"selected,omitempty"`
+	Clips       []Clip                  `json:"clips,omitempty"`
+}
+
+// NewCheckpoint captures a job's durable state
+func NewCheckpoint(job *Job) Checkpoint {
+	return Checkpoint{
+		Options:     job.Options,
+		ContentHash: job.ContentHash,
+		FromCache:   job.FromCache,
+		Media:       job.Media,
+		Transcript:  job.Transcript,
+		Scenes:      job.Scenes,
+		Frames:      job.Frames,
+		Candidates:  job.Candidates,
+		Selected:    job.Selected,
+		Clips:       job.Clips,
+	}
+}
+
+// Restore copies a checkpoint's state into a job
+func (c Checkpoint) Restore(job *Job) {
+	job.Options = c.Options
+	job.ContentHash = c.ContentHash
+	job.FromCache = c.FromCache
+	job.Media = c.Media
+	job.Transcript = c.Transcript
+	job.Scenes = c.Scenes
+	job.Frames = c.Frames
+	job.Candidates = c.Candidates
+	job.Selected = c.Selected
+	job.Clips = c.Clips
+}
+
+// DecodeCheckpoint parses a checkpoint saved by the Recorder
+func DecodeCheckpoint(data []byte) (Checkpoint, error) {
+	var c Checkpoint
+	if err := json.Unmarshal(data, &c); err != nil {
+		return Checkpoint{}, fmt.Errorf("failed to decode checkpoint: %w", err)
+	}
+	return c, nil
+}
+
+// RestoreJob prepares a recorded job for resuming: it restores the latest
+// checkpoint, or just the options if no stage completed, brings back clips
+// that were extracted before a failure and returns the stage to resume from
+func RestoreJob(ctx context.Context, db *store.Store, record *store.Job, job *Job) (int, error) {
+	completed, data, err := db.LoadCheckpoint(ctx, record.ID)
+	if err != nil {
+		return 0, err
+	}
+
+	if data != nil {
+		checkpoint, err := DecodeCheckpoint(data)
+		if err != nil {
+			return 0, err
+		}
+		checkpoint.Restore(job)
+	} else if err := json.Unmarshal([]byte(record.ModelParameters), &job.Options); err != nil {
+		return 0, fmt.Errorf("failed to decode options of job %s: %w", record.Name, err)
+	}
+
+	// A failed extraction stage leaves no checkpoint, but the clips it did
+	// write were recorded
+	if len(job.Clips) == 0 {
+		clips, err := db.ListClips(ctx, record.ID)
+		if err != nil {
+			return 0, err
+		}
+		for _, c := range clips {
+			job.Clips = append(job.Clips, clipFromStore(c))
+		}
+	}
+
+	return completed + 1, nil
+}
@@ -0,0 +1,102 @@
+package pipeline
+
+import (
+	"path/filepath"
+	"time"
+
+	"ai-video-editor/processing/ai"
+	"ai-video-editor/processing/analysis"
+	"ai-video-editor/processing/captions"
+	"ai-video-editor/processing/store"
+	"ai-video-editor/processing/toolchain"
+	"ai-video-editor/processing/video"
+	"ai-video-editor/processing/workspace"
+)
+
+// Options are the user's choices for a processing run. They are stored with
+// the job so it can be resumed with the same settings.
+type Options struct {
+	OutputDir string                 `json:"output_dir"` // Parent of each job's own directory, see Job.OutputDir
+	Duration  analysis.DurationRange `json:"duration"`   // Allowed clip length
+	MaxClips  int                    `json:"max_clips"`
+	Quality   string                 `json:"quality"` // Preset name, for display
+	Encode    video.EncodeSettings   `json:"encode"`  // The resolved quality preset
+	SkipAudio bool                   `json:"skip_audio"`
+	Chunks    video.ChunkOptions     `json:"chunks"`
+	Captions  captions.Options       `json:"captions"`
+
+	// BurnCaptions renders karaoke captions in CaptionStyle into the clips
+	BurnCaptions bool           `json:"burn_captions"`
+	CaptionStyle captions.Style `json:"caption_style"`
+
+	// SceneThreshold is the scene change score that starts a new scene, or 0
+	// to skip scene detection
+	SceneThreshold float64 `json:"scene_threshold"`
+
+	// Reframe changes the clips' aspect ratio, e.g. to 9:16 for vertical video
+	Reframe video.ReframeOptions `json:"reframe"`
+
+	// CacheKey identifies the analysis settings for the cache, see CacheKey.
+	// NoCache ignores existing entries; fresh results still replace them.
+	CacheKey string `json:"cache_key"`
+	NoCache  bool   `json:"no_cache"`
+}
+
+// Job is the shared state passed from stage to stage. Each stage reads the
+// fields earlier stages filled in and adds its own.
+type Job struct {
+	ID      string
+	Input   string
+	Prompt  string
+	Options Options
+
+	Workspace   *workspace.Workspace
+	Toolchain   *toolchain.Toolchain
+	Transcriber ai.Transcriber // nil when audio is skipped
+	Cache       *store.Store   // nil disables the analysis cache
+
+	// Filled in by stages, in order
+	ContentHash string
+	FromCache   bool // Probe, transcript, scenes and frames came from the analysis cache
+	Media       video.MediaInfo
+	Transcript  ai.Transcript
+	Scenes      []time.Duration         // Scene boundaries, when detected
+	Frames      []video.FrameDescriptor // The first frame of each scene
+	Candidates  []analysis.Candidate
+	Selected    []analysis.Candidate
+	Clips       []Clip
+
+	progress func(done, total float64) // Set by the runner for the current stage
+}
+
+// OutputDir returns the directory the job writes its clips to, a
+// subdirectory of Options.OutputDir named after the job so that runs on the
+// same source never overwrite each other's files
+func (j *Job) OutputDir() string {
+	return filepath.Join(j.Options.OutputDir, j.ID)
+}
+
+// ReportProgress tells the runner's observer how far the current stage has got
+func (j *Job) ReportProgress(done, total float64) {
+	if j.progress != nil && total > 0 {
+		j.progress(min(done, total), total)
+	}
+}
+
+// Clip is a generated output file
+type Clip struct {
+	Index         int           `json:"index"`
+	Start         time.Duration `json:"start"`
+	End           time.Duration `json:"end"`
+	Score         float64       `json:"score"`
+	Reason        string        `json:"reason"`
+	Text          string        `json:"text"`
+	Path          string        `json:"path"`
+	CaptionPaths  []string      `json:"caption_paths,omitempty"`
+	ThumbnailPath string        `json:"thumbnail_path,omitempty"`
+}
+
+// Duration returns the clip's length
+func (c Clip) Duration() time.Duration {
+	return c.End - c.Start
+}
@@ -0,0 +1,147 @@
+package pipeline
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"ai-video-editor/processing/ai"
+	"ai-video-editor/processing/store"
+)
+
+// Observers fans runner notifications out to several observers in order
+type Observers []Observer
+
+func (o Observers) StageStarted(job *Job, index, total int, stage Stage) {
+	for _, observer := range o {
+		observer.StageStarted(job, index, total, stage)
+	}
+}
+
+func (o Observers) StageFinished(job *Job, stage Stage, elapsed time.Duration, err error) {
+	for _, observer := range o {
+		observer.StageFinished(job, stage, elapsed, err)
+	}
+}
+
+func (o Observers) StageProgress(job *Job, stage Stage, done, total float64) {
+	for _, observer := range o {
+		if po, ok := observer.(ProgressObserver); ok {
+			po.StageProgress(job, stage, done, total)
+		}
+	}
+}
+
+// Recorder keeps a job's database record in step with the runner: a row
+// per stage with its timing and outcome, a checkpoint after every completed
+// stage, the planned clip count after selection and a row per extracted clip,
+// updated with its caption files once they're written.
+// Database errors never stop the pipeline; the first one is available from Err.
+type Recorder struct {
+	Store *store.Store
+	JobID int64
+
+	index, total int
+	err          error
+}
+
+func (r *Recorder) StageStarted(job *Job, index, total int, stage Stage) {
+	r.index, r.total = index, total
+	r.record(r.Store.StartStage(context.Background(), r.JobID, index, stage.Name()))
+}
+
+func (r *Recorder) StageFinished(job *Job, stage Stage, elapsed time.Duration, err error) {
+	// The run's own context may already be cancelled; these writes are quick
+	ctx := context.Background()
+
+	r.record(r.Store.FinishStage(ctx, r.JobID, r.index, elapsed, err))
+
+	// Clips that did extract are kept even when others failed, so resume
+	// only has to redo the failures
+	switch stage.(type) {
+	case *ExtractionStage, *CaptionStage:
+		for _, clip := range job.Clips {
+			r.record(r.Store.SaveClip(ctx, storeClip(r.JobID, job, clip)))
+		}
+	}
+	if err != nil {
+		return
+	}
+
+	if _, ok := stage.(*SelectionStage); ok {
+		r.record(r.Store.SetClipsPlanned(ctx, r.JobID, len(job.Selected)))
+	}
+
+	if data, err := json.Marshal(NewCheckpoint(job)); err != nil {
+		r.record(fmt.Errorf("failed to encode checkpoint: %w", err))
+	} else {
+		r.record(r.Store.SaveCheckpoint(ctx, r.JobID, r.index, data))
+	}
+
+	if r.total > 0 {
+		r.record(r.Store.UpdateProgress(ctx, r.JobID, float64(r.index+1)/float64(r.total)))
+	}
+}
+
+// Err returns the first database error seen, if any
+func (r *Recorder) Err() error {
+	return r.err
+}
+
+func (r *Recorder) record(err error) {
+	if err != nil && r.err == nil {
+		r.err = err
+	}
+}
+
+// storeClip converts a generated clip into its database record
+func storeClip(jobID int64, job *Job, clip Clip) *store.Clip {
+	return &store.Clip{
+		JobID:           jobID,
+		Name:            filepath.Base(clip.Path),
+		FilePath:        clip.Path,
+		Index:           clip.Index,
+		Start:           clip.Start,
+		End:             clip.End,
+		RelevanceScore:  clip.Score,
+		ConfidenceScore: wordConfidence(job.Transcript.Slice(clip.Start, clip.End)),
+		Reason:          clip.Reason,
+		TranscriptText:  clip.Text,
+		CaptionPaths:    clip.CaptionPaths,
+		ThumbnailPath:   clip.ThumbnailPath,
+	}
+}
+
+// wordConfidence averages the transcription confidence of a transcript's
+// words, or returns 0 when the backend didn't report any
+func wordConfidence(t ai.Transcript) float64 {
+	var sum float64
+	var n int
+	for _, w := range t.Words() {
+		if w.Confidence > 0 {
+			sum += w.Confidence
+			n++
+		}
+	}
+	if n == 0 {
+		return 0
+	}
+	return sum / float64(n)
+}
+
+// clipFromStore converts a clip's database record back into a pipeline clip
+func clipFromStore(c *store.Clip) Clip {
+	return Clip{
+		Index:         c.Index,
+		Start:         c.Start,
+		End:           c.End,
+		Score:         c.RelevanceScore,
+		Reason:        c.Reason,
+		Text:          c.TranscriptText,
+		Path:          c.FilePath,
+		CaptionPaths:  c.CaptionPaths,
+		ThumbnailPath: c.ThumbnailPath,
+	}
+}
@@ -0,0 +1,92 @@
+package pipeline
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// Stage is one step of the processing pipeline
+type Stage interface {
+	Name() string
+	Run(ctx context.Context, job *Job) error
+}
+
+// Observer is notified as the runner moves through stages
+type Observer interface {
+	StageStarted(job *Job, index, total int, stage Stage)
+	StageFinished(job *Job, stage Stage, elapsed time.Duration, err error)
+}
+
+// ProgressObserver is an Observer that also wants progress within a stage.
+// done and total are in whatever unit the stage counts in, such as seconds of
+// media or chunks transcribed.
+type ProgressObserver interface {
+	Observer
+	StageProgress(job *Job, stage Stage, done, total float64)
+}
+
+// Runner executes stages in order against a job
+type Runner struct {
+	Stages   []Stage
+	Observer Observer
+}
+
+// NewRunner creates a runner for the default eight stage pipeline
+func NewRunner(observer Observer) *Runner {
+	return &Runner{
+		Stages:   DefaultStages(),
+		Observer: observer,
+	}
+}
+
+// DefaultStages returns the full pipeline from metadata to final output
+func DefaultStages() []Stage {
+	return []Stage{
+		&MetadataStage{},
+		&SceneStage{},
+		&TranscriptionStage{},
+		&AnalysisStage{},
+		&SelectionStage{},
+		&ExtractionStage{},
+		&CaptionStage{},
+		&FinalizeStage{},
+	}
+}
+
+// Run executes every stage in order, stopping at the first failure or when
+// ctx is cancelled
+func (r *Runner) Run(ctx context.Context, job *Job) error {
+	return r.RunFrom(ctx, job, 0)
+}
+
+// RunFrom executes the stages from index start onwards, for resuming a job
+// whose earlier stages were restored from a checkpoint
+func (r *Runner) RunFrom(ctx context.Context, job *Job, start int) error {
+	for i := start; i < len(r.Stages); i++ {
+		stage := r.Stages[i]
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		if r.Observer != nil {
+			r.Observer.StageStarted(job, i, len(r.Stages), stage)
+		}
+
+		job.progress = nil
+		if po, ok := r.Observer.(ProgressObserver); ok {
+			job.progress = func(done, total float64) { po.StageProgress(job, stage, done, total) }
+		}
+
+		started := time.Now()
+		err := stage.Run(ctx, job)
+
+		if r.Observer != nil {
+			r.Observer.StageFinished(job, stage, time.Since(started), err)
+		}
+		if err != nil {
+			return fmt.Errorf("%s: %w", stage.Name(), err)
+		}
+	}
+	return nil
+}
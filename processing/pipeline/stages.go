@@ -0,0 +1,472 @@
+package pipeline
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"ai-video-editor/processing/ai"
+	"ai-video-editor/processing/analysis"
+	"ai-video-editor/processing/captions"
+	"ai-video-editor/processing/store"
+	"ai-video-editor/processing/video"
+)
+
+// MetadataStage probes the input file
+type MetadataStage struct{}
+
+func (s *MetadataStage) Name() string { return "Analyzing video metadata" }
+
+func (s *MetadataStage) Run(ctx context.Context, job *Job) error {
+	info, err := probeOrLoadCached(ctx, job)
+	if err != nil {
+		return err
+	}
+	if !info.HasVideo() {
+		return fmt.Errorf("%s has no video stream", job.Input)
+	}
+
+	// Fail before any expensive work if the requested clip length can't be met
+	length, err := job.Options.Duration.Fit(info.Duration)
+	if err != nil {
+		return fmt.Errorf("cannot cut %s clips: %w", job.Options.Duration, err)
+	}
+	job.Options.Duration = length
+
+	job.Media = info
+	return nil
+}
+
+// probeOrLoadCached hashes the input and restores earlier analysis from the
+// cache, falling back to probing the file
+func probeOrLoadCached(ctx context.Context, job *Job) (video.MediaInfo, error) {
+	if job.Cache != nil {
+		hash, err := store.HashFile(ctx, job.Input)
+		if err != nil {
+			return video.MediaInfo{}, err
+		}
+		job.ContentHash = hash
+
+		if !job.Options.NoCache {
+			hit, err := loadCachedAnalysis(ctx, job)
+			if err != nil {
+				return video.MediaInfo{}, err
+			}
+			if hit {
+				job.FromCache = true
+				return job.Media, nil
+			}
+		}
+	}
+
+	// Only the probe is needed here; later stages stream audio and cut clips
+	// straight from the source, so nothing is extracted up front
+	result, err := video.Analyze(ctx, job.Input, video.AnalyzeOptions{
+		FFmpegPath:  job.Toolchain.FFmpeg.Path,
+		FFprobePath: job.Toolchain.FFprobe.Path,
+		Workspace:   job.Workspace,
+		SkipAudio:   true,
+		SkipVideo:   true,
+	})
+	if err != nil {
+		return video.MediaInfo{}, err
+	}
+	return result.Info, nil
+}
+
+// SceneStage finds the scene changes in the source and describes the first
+// frame of each new scene
+type SceneStage struct{}
+
+func (s *SceneStage) Name() string { return "Detecting scene changes" }
+
+func (s *SceneStage) Run(ctx context.Context, job *Job) error {
+	if job.FromCache || job.Options.SceneThreshold <= 0 {
+		return nil
+	}
+
+	extractor := &video.VideoExtractor{
+		TempDir:     job.Workspace.Dir,
+		FFmpegPath:  job.Toolchain.FFmpeg.Path,
+		FFprobePath: job.Toolchain.FFprobe.Path,
+		Workspace:   job.Workspace,
+	}
+	frames, errs := extractor.SampleFrames(ctx, job.Input, video.FrameSampleOptions{
+		SceneThreshold: job.Options.SceneThreshold,
+		Width:          sceneSampleWidth,
+		PixelFormat:    video.PixelRGB24,
+	}, 4)
+
+	job.Scenes, job.Frames = nil, nil
+	for frame := range frames {
+		job.Scenes = append(job.Scenes, frame.Timestamp)
+		job.Frames = append(job.Frames, frame.Describe())
+		job.ReportProgress(frame.Timestamp.Seconds(), job.Media.Duration.Seconds())
+	}
+	if err := <-errs; err != nil {
+		return fmt.Errorf("failed to detect scene changes: %w", err)
+	}
+	return nil
+}
+
+// sceneSampleWidth is the width frames are scaled to for scene detection
+const sceneSampleWidth = 160
+
+// TranscriptionStage decodes the audio track in a single pass, transcribes
+// it chunk by chunk as it streams in, stitches the results and stores the
+// analysis so far in the cache. No audio is written to disk.
+type TranscriptionStage struct{}
+
+func (s *TranscriptionStage) Name() string { return "Performing speech-to-text transcription" }
+
+func (s *TranscriptionStage) Run(ctx context.Context, job *Job) error {
+	if job.FromCache {
+		return nil
+	}
+	if job.Options.SkipAudio || !job.Media.HasAudio() || job.Transcriber == nil {
+		return saveAnalysis(ctx, job)
+	}
+
+	extractor := video.NewAudioExtractor(job.Workspace.Dir)
+	extractor.FFmpegPath = job.Toolchain.FFmpeg.Path
+
+	var parts []ai.ChunkTranscript
+	total := job.Media.Duration
+	job.ReportProgress(0, total.Seconds())
+	err := extractor.StreamChunks(ctx, job.Input, total, job.Options.Chunks, func(chunk video.PCMChunk) error {
+		t, err := job.Transcriber.Transcribe(ctx, ai.Audio{Samples: chunk.Samples})
+		if err != nil {
+			return fmt.Errorf("chunk %d at %s: %w", chunk.Index, chunk.Start, err)
+		}
+		parts = append(parts, ai.ChunkTranscript{
+			Offset:     chunk.Start,
+			Duration:   chunk.Duration,
+			Transcript: t,
+		})
+		job.ReportProgress((chunk.Start + chunk.Duration).Seconds(), total.Seconds())
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	job.Transcript = ai.MergeChunks(parts)
+	return saveAnalysis(ctx, job)
+}
+
+// AnalysisStage scores stretches of the transcript against the prompt. Without
+// a transcript it falls back to the scene changes, or to even windows.
+type AnalysisStage struct{}
+
+func (s *AnalysisStage) Name() string { return "Running AI content analysis" }
+
+func (s *AnalysisStage) Run(ctx context.Context, job *Job) error {
+	if len(job.Transcript.Segments) == 0 {
+		if len(job.Scenes) > 0 {
+			job.Candidates = analysis.SceneCandidates(job.Scenes, job.Media.Duration, job.Options.Duration.Target)
+		} else {
+			job.Candidates = analysis.EvenCandidates(job.Media.Duration, job.Options.Duration.Target)
+		}
+		return nil
+	}
+	job.Candidates = analysis.ScoreTranscript(job.Transcript, job.Prompt, job.Options.Duration.Target)
+	return nil
+}
+
+// SelectionStage picks the clips to produce
+type SelectionStage struct{}
+
+func (s *SelectionStage) Name() string { return "Identifying clip segments" }
+
+func (s *SelectionStage) Run(ctx context.Context, job *Job) error {
+	job.Selected = analysis.Select(job.Candidates, job.Media.Duration, analysis.SelectOptions{
+		MaxClips: job.Options.MaxClips,
+		Length:   job.Options.Duration,
+	})
+	if len(job.Selected) == 0 {
+		return fmt.Errorf("no clip candidates found")
+	}
+	return nil
+}
+
+// ExtractionStage cuts each selected segment into the job's output directory
+// and saves a thumbnail of each clip.
+// Clips already in job.Clips whose file exists are kept, so a resumed job
+// only re-encodes the clips that failed.
+type ExtractionStage struct{}
+
+func (s *ExtractionStage) Name() string { return "Extracting video clips" }
+
+func (s *ExtractionStage) Run(ctx context.Context, job *Job) error {
+	encode := job.Options.Encode
+	if encode.VideoCodec == "" {
+		encode = video.QualityPresets[video.DefaultQuality]
+	}
+
+	// Stream copies keep the source's codecs, resolution and bitrates, so only
+	// allow them when the source already matches the preset
+	mode := video.ClipModeReencode
+	if encode.Fits(job.Media) {
+		mode = video.ClipModeAuto
+	}
+
+	// Reframing and burned-in captions are laid out for the displayed frame
+	var width, height int
+	if stream, ok := job.Media.VideoStream(); ok {
+		width, height = stream.DisplaySize()
+	}
+	if job.Options.Reframe.Enabled() && (width == 0 || height == 0) {
+		return fmt.Errorf("cannot reframe %s: no video stream with a known frame size", job.Input)
+	}
+
+	if err := os.MkdirAll(job.OutputDir(), 0755); err != nil {
+		return fmt.Errorf("failed to create output directory: %w", err)
+	}
+
+	extractor := &video.VideoExtractor{
+		TempDir:     job.Workspace.Dir,
+		FFmpegPath:  job.Toolchain.FFmpeg.Path,
+		FFprobePath: job.Toolchain.FFprobe.Path,
+		Workspace:   job.Workspace,
+	}
+
+	done := map[int]Clip{}
+	for _, clip := range job.Clips {
+		if _, err := os.Stat(clip.Path); err == nil {
+			done[clip.Index] = clip
+		}
+	}
+
+	// Progress is seconds of output written across all clips
+	var total, written time.Duration
+	for _, seg := range job.Selected {
+		total += seg.Duration()
+	}
+
+	// One bad segment shouldn't throw away the others; failures are
+	// reported together once every clip has been tried
+	var failed []string
+	job.Clips = job.Clips[:0]
+	for i, seg := range job.Selected {
+		if clip, ok := done[i+1]; ok {
+			job.Clips = append(job.Clips, clip)
+			written += seg.Duration()
+			job.ReportProgress(written.Seconds(), total.Seconds())
+			continue
+		}
+
+		opts := video.DefaultClipOptions()
+		opts.Mode = mode
+		opts.Encode = encode
+		opts.OutputPath = clipPath(job, i+1, ".mp4")
+		opts.Reframe = job.Options.Reframe
+		opts.FrameWidth, opts.FrameHeight = width, height
+		if job.Options.BurnCaptions {
+			subtitles, err := writeBurnedCaptions(job, seg.Start, seg.End, width, height)
+			if err != nil {
+				return err
+			}
+			opts.Subtitles = subtitles
+		}
+		opts.Progress = func(d time.Duration) {
+			job.ReportProgress((written + d).Seconds(), total.Seconds())
+		}
+
+		result, err := extractor.ExtractClip(ctx, job.Input, seg.Start, seg.End, opts)
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			failed = append(failed, fmt.Sprintf("clip %d: %v", i+1, err))
+			written += seg.Duration()
+			continue
+		}
+		written += seg.Duration()
+		job.ReportProgress(written.Seconds(), total.Seconds())
+
+		job.Clips = append(job.Clips, Clip{
+			Index:  i + 1,
+			Start:  result.Start,
+			End:    result.End,
+			Score:  seg.Score,
+			Reason: seg.Reason,
+			Text:   job.Transcript.Slice(result.Start, result.End).Text,
+			Path:   result.Path,
+		})
+	}
+
+	// Clips restored on resume may predate a thumbnail failure, so any clip
+	// without one gets it now
+	var noThumbnail []string
+	for i := range job.Clips {
+		clip := &job.Clips[i]
+		if clip.ThumbnailPath != "" {
+			if _, err := os.Stat(clip.ThumbnailPath); err == nil {
+				continue
+			}
+		}
+		path, err := writeThumbnail(ctx, extractor, *clip)
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			noThumbnail = append(noThumbnail, fmt.Sprintf("clip %d: %v", clip.Index, err))
+			continue
+		}
+		clip.ThumbnailPath = path
+	}
+
+	if len(failed) > 0 {
+		return fmt.Errorf("%d of %d clips failed to extract: %s", len(failed), len(job.Selected), strings.Join(failed, "; "))
+	}
+	if len(noThumbnail) > 0 {
+		return fmt.Errorf("failed to write thumbnails: %s", strings.Join(noThumbnail, "; "))
+	}
+	return nil
+}
+
+// writeThumbnail saves a frame from the middle of a clip next to it as the
+// clip's cover image. The frame is taken from the finished clip so it shows
+// any reframing and burned-in captions.
+func writeThumbnail(ctx context.Context, extractor *video.VideoExtractor, clip Clip) (string, error) {
+	// Decode into the output directory so the final rename never crosses filesystems
+	dir, err := os.MkdirTemp(filepath.Dir(clip.Path), ".thumbnail-*")
+	if err != nil {
+		return "", fmt.Errorf("failed to create thumbnail directory: %w", err)
+	}
+	defer os.RemoveAll(dir)
+
+	at := clip.Duration() / 2
+	frames, err := extractor.ExtractFrameImages(ctx, clip.Path, video.FrameSampleOptions{
+		Start:    at,
+		End:      at + time.Second,
+		Interval: time.Second,
+	}, dir, ".jpg")
+	if err != nil {
+		return "", err
+	}
+	if len(frames) == 0 {
+		return "", fmt.Errorf("no frame decoded at %s", at)
+	}
+
+	path := strings.TrimSuffix(clip.Path, filepath.Ext(clip.Path)) + ".jpg"
+	if err := os.Rename(frames[0].Path, path); err != nil {
+		return "", fmt.Errorf("failed to save thumbnail: %w", err)
+	}
+	return path, nil
+}
+
+// writeBurnedCaptions writes the ASS script burned into the clip between
+// start and end of a width x height source, or returns "" when nothing is
+// said in it
+func writeBurnedCaptions(job *Job, start, end time.Duration, width, height int) (string, error) {
+	cues := captions.BuildCues(job.Transcript, start, end, captionOptions(job))
+	if len(cues) == 0 {
+		return "", nil
+	}
+	if width > 0 && height > 0 {
+		width, height = job.Options.Reframe.OutputSize(width, height)
+	}
+
+	path, err := job.Workspace.NewFile("captions", ".ass")
+	if err != nil {
+		return "", err
+	}
+	write := func(w io.Writer, cues []captions.Cue) error {
+		return captions.WriteASS(w, cues, job.Options.CaptionStyle, width, height)
+	}
+	if err := captions.WriteFile(path, cues, write); err != nil {
+		return "", err
+	}
+	return path, nil
+}
+
+// captionOptions returns the job's caption limits
+func captionOptions(job *Job) captions.Options {
+	if job.Options.Captions.MaxLines == 0 {
+		// Jobs recorded before captions were configurable
+		return captions.DefaultOptions()
+	}
+	return job.Options.Captions
+}
+
+// CaptionStage writes SRT and WebVTT subtitles next to each clip, timed from
+// the clip's start
+type CaptionStage struct{}
+
+func (s *CaptionStage) Name() string { return "Generating captions" }
+
+func (s *CaptionStage) Run(ctx context.Context, job *Job) error {
+	opts := captionOptions(job)
+
+	for i := range job.Clips {
+		clip := &job.Clips[i]
+		clip.CaptionPaths = nil
+
+		cues := captions.BuildCues(job.Transcript, clip.Start, clip.End, opts)
+		if len(cues) == 0 {
+			continue
+		}
+
+		base := strings.TrimSuffix(clip.Path, filepath.Ext(clip.Path))
+		for _, format := range []struct {
+			ext   string
+			write func(io.Writer, []captions.Cue) error
+		}{
+			{".srt", captions.WriteSRT},
+			{".vtt", captions.WriteVTT},
+		} {
+			path := base + format.ext
+			if err := captions.WriteFile(path, cues, format.write); err != nil {
+				return fmt.Errorf("failed to write captions for clip %d: %w", clip.Index, err)
+			}
+			clip.CaptionPaths = append(clip.CaptionPaths, path)
+		}
+	}
+	return nil
+}
+
+// FinalizeStage writes a summary of the run next to the clips
+type FinalizeStage struct{}
+
+func (s *FinalizeStage) Name() string { return "Finalizing output files" }
+
+func (s *FinalizeStage) Run(ctx context.Context, job *Job) error {
+	summary := struct {
+		JobID  string `json:"job_id"`
+		Input  string `json:"input"`
+		Prompt string `json:"prompt"`
+		Clips  []Clip `json:"clips"`
+	}{job.ID, job.Input, job.Prompt, job.Clips}
+
+	data, err := json.MarshalIndent(summary, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode summary: %w", err)
+	}
+
+	path := filepath.Join(job.OutputDir(), SummaryName(job.Input))
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write summary: %w", err)
+	}
+	return nil
+}
+
+// SummaryName returns the file name of the run summary FinalizeStage writes
+// into a job's output directory
+func SummaryName(input string) string {
+	return baseName(input) + "_clips.json"
+}
+
+// clipPath returns the output path for the nth clip of a job
+func clipPath(job *Job, n int, ext string) string {
+	return filepath.Join(job.OutputDir(), fmt.Sprintf("%s_clip_%02d%s", baseName(job.Input), n, ext))
+}
+
+func baseName(path string) string {
+	return strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+}
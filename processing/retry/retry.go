@@ -0,0 +1,242 @@
+package retry
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Policy controls how an operation is retried
+type Policy struct {
+	MaxAttempts       int           // Total attempts including the first
+	InitialDelay      time.Duration // Delay before the first retry
+	MaxDelay          time.Duration // Upper bound for any single delay
+	Multiplier        float64       // Growth factor between delays
+	Jitter            float64       // Fraction of each delay randomised, 0 to 1
+	PerAttemptTimeout time.Duration // Zero means attempts are bounded only by ctx
+
+	// OnRetry is called before sleeping ahead of each retry
+	OnRetry func(attempt int, delay time.Duration, err error)
+}
+
+// DefaultPolicy suits remote inference APIs, where cold models can take tens
+// of seconds to load
+func DefaultPolicy() Policy {
+	return Policy{
+		MaxAttempts:       8,
+		InitialDelay:      2 * time.Second,
+		MaxDelay:          45 * time.Second,
+		Multiplier:        2,
+		Jitter:            0.2,
+		PerAttemptTimeout: 10 * time.Minute,
+	}
+}
+
+// Error wraps a failure with its retry classification
+type Error struct {
+	Err        error
+	Retryable  bool
+	RetryAfter time.Duration // Server requested delay, zero if none
+	StatusCode int           // HTTP status, zero for non-HTTP errors
+}
+
+func (e *Error) Error() string {
+	return e.Err.Error()
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Retryable marks err as worth retrying
+func Retryable(err error) error {
+	return &Error{Err: err, Retryable: true}
+}
+
+// Fatal marks err as permanent; Do returns it immediately
+func Fatal(err error) error {
+	return &Error{Err: err}
+}
+
+// HTTPError classifies a non-2xx response. 408, 425, 429 and 5xx are
+// retryable, honouring Retry-After and Hugging Face's estimated_time;
+// everything else (401 bad key, 400 bad audio, ...) is fatal.
+func HTTPError(resp *http.Response, body []byte) error {
+	e := &Error{
+		Err:        fmt.Errorf("%s: %s", resp.Status, strings.TrimSpace(string(body))),
+		StatusCode: resp.StatusCode,
+	}
+
+	switch resp.StatusCode {
+	case http.StatusRequestTimeout, http.StatusTooEarly, http.StatusTooManyRequests:
+		e.Retryable = true
+	default:
+		e.Retryable = resp.StatusCode >= 500 && resp.StatusCode != http.StatusNotImplemented
+	}
+
+	if e.Retryable {
+		e.RetryAfter = parseRetryAfter(resp.Header.Get("Retry-After"))
+
+		// Hugging Face answers 503 with {"error": "Model ... is currently loading", "estimated_time": 20.0}
+		if e.RetryAfter == 0 && resp.StatusCode == http.StatusServiceUnavailable {
+			var loading struct {
+				EstimatedTime float64 `json:"estimated_time"`
+			}
+			if json.Unmarshal(body, &loading) == nil && loading.EstimatedTime > 0 {
+				e.RetryAfter = time.Duration(loading.EstimatedTime * float64(time.Second))
+			}
+		}
+	}
+
+	return e
+}
+
+// Classify reports whether err should be retried and any server requested delay.
+// Unclassified network timeouts and connection errors are retryable; anything
+// else is fatal.
+func Classify(err error) (retryable bool, after time.Duration) {
+	var e *Error
+	if errors.As(err, &e) {
+		return e.Retryable, e.RetryAfter
+	}
+	if errors.Is(err, context.Canceled) {
+		return false, 0
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return true, 0
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return true, 0
+	}
+	var opErr *net.OpError
+	if errors.As(err, &opErr) {
+		return true, 0
+	}
+	return false, 0
+}
+
+// Do runs fn until it succeeds, returns a fatal error, attempts run out or
+// ctx is cancelled. Each attempt gets its own timeout when the policy sets one.
+func Do(ctx context.Context, p Policy, fn func(ctx context.Context) error) error {
+	if p.MaxAttempts <= 0 {
+		p.MaxAttempts = 1
+	}
+
+	var err error
+	for attempt := 1; ; attempt++ {
+		err = runAttempt(ctx, p, fn)
+		if err == nil {
+			return nil
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		retryable, after := Classify(err)
+		if !retryable {
+			return err
+		}
+		if attempt >= p.MaxAttempts {
+			return fmt.Errorf("giving up after %d attempts: %w", attempt, err)
+		}
+
+		delay := p.backoff(attempt)
+		if after > 0 {
+			// Waiting past the deadline only to be cancelled wastes the time
+			// the caller has left; report the server's answer instead
+			if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < after {
+				return fmt.Errorf("server asked to retry in %s, past the deadline: %w", after.Round(time.Second), err)
+			}
+			if p.MaxDelay > 0 {
+				after = min(after, p.MaxDelay)
+			}
+			delay = max(delay, after)
+		}
+		if p.OnRetry != nil {
+			p.OnRetry(attempt, delay, err)
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		}
+	}
+}
+
+// DoValue is Do for operations that return a value
+func DoValue[T any](ctx context.Context, p Policy, fn func(ctx context.Context) (T, error)) (T, error) {
+	var result T
+	err := Do(ctx, p, func(ctx context.Context) error {
+		var err error
+		result, err = fn(ctx)
+		return err
+	})
+	return result, err
+}
+
+func runAttempt(ctx context.Context, p Policy, fn func(ctx context.Context) error) error {
+	if p.PerAttemptTimeout <= 0 {
+		return fn(ctx)
+	}
+
+	attemptCtx, cancel := context.WithTimeout(ctx, p.PerAttemptTimeout)
+	defer cancel()
+
+	err := fn(attemptCtx)
+	if err != nil && ctx.Err() == nil && attemptCtx.Err() == context.DeadlineExceeded {
+		return Retryable(fmt.Errorf("attempt timed out after %s: %w", p.PerAttemptTimeout, err))
+	}
+	return err
+}
+
+// backoff returns the exponential delay before retry number attempt, with
+// jitter, never above MaxDelay
+func (p Policy) backoff(attempt int) time.Duration {
+	multiplier := p.Multiplier
+	if multiplier < 1 {
+		multiplier = 1
+	}
+
+	delay := float64(p.InitialDelay) * math.Pow(multiplier, float64(attempt-1))
+	if p.MaxDelay > 0 && delay > float64(p.MaxDelay) {
+		delay = float64(p.MaxDelay)
+	}
+
+	if p.Jitter > 0 {
+		jitter := math.Min(p.Jitter, 1)
+		delay *= 1 - jitter + 2*jitter*rand.Float64()
+	}
+	if p.MaxDelay > 0 && delay > float64(p.MaxDelay) {
+		delay = float64(p.MaxDelay)
+	}
+	return time.Duration(delay)
+}
+
+// parseRetryAfter accepts both delta-seconds and HTTP-date forms
+func parseRetryAfter(value string) time.Duration {
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	if t, err := http.ParseTime(value); err == nil {
+		if d := time.Until(t); d > 0 {
+			return d
+		}
+	}
+	return 0
+}
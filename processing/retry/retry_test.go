@@ -0,0 +1,151 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func response(status int, retryAfter string) *http.Response {
+	resp := &http.Response{StatusCode: status, Status: http.StatusText(status), Header: http.Header{}}
+	if retryAfter != "" {
+		resp.Header.Set("Retry-After", retryAfter)
+	}
+	return resp
+}
+
+func TestClassify(t *testing.T) {
+	opErr := &net.OpError{Op: "dial", Net: "tcp", Err: syscall.ECONNREFUSED}
+
+	tests := []struct {
+		name          string
+		err           error
+		wantRetryable bool
+		wantAfter     time.Duration
+	}{
+		{"408 request timeout", HTTPError(response(http.StatusRequestTimeout, ""), nil), true, 0},
+		{"425 too early", HTTPError(response(http.StatusTooEarly, ""), nil), true, 0},
+		{"429 too many requests", HTTPError(response(http.StatusTooManyRequests, ""), nil), true, 0},
+		{"500 internal error", HTTPError(response(http.StatusInternalServerError, ""), nil), true, 0},
+		{"502 bad gateway", HTTPError(response(http.StatusBadGateway, ""), nil), true, 0},
+		{"503 model loading", HTTPError(response(http.StatusServiceUnavailable, ""), []byte(`{"error":"loading","estimated_time":20.5}`)), true, 20500 * time.Millisecond},
+		{"501 not implemented", HTTPError(response(http.StatusNotImplemented, ""), nil), false, 0},
+		{"400 bad request", HTTPError(response(http.StatusBadRequest, ""), nil), false, 0},
+		{"401 unauthorized", HTTPError(response(http.StatusUnauthorized, ""), nil), false, 0},
+		{"404 not found", HTTPError(response(http.StatusNotFound, ""), nil), false, 0},
+		{"Retry-After seconds", HTTPError(response(http.StatusTooManyRequests, "7"), nil), true, 7 * time.Second},
+		{"Retry-After seconds wins over estimated_time", HTTPError(response(http.StatusServiceUnavailable, "3"), []byte(`{"estimated_time":20}`)), true, 3 * time.Second},
+		{"Retry-After ignored on fatal errors", HTTPError(response(http.StatusBadRequest, "7"), nil), false, 0},
+		{"Retry-After garbage", HTTPError(response(http.StatusTooManyRequests, "soon"), nil), true, 0},
+		{"Retry-After date in the past", HTTPError(response(http.StatusTooManyRequests, "Mon, 02 Jan 2006 15:04:05 GMT"), nil), true, 0},
+		{"net.OpError", opErr, true, 0},
+		{"wrapped net.OpError", fmt.Errorf("request failed: %w", opErr), true, 0},
+		{"deadline exceeded", context.DeadlineExceeded, true, 0},
+		{"cancelled", context.Canceled, false, 0},
+		{"explicitly retryable", Retryable(errors.New("flaky")), true, 0},
+		{"explicitly fatal", Fatal(opErr), false, 0},
+		{"plain error", errors.New("bad input"), false, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			retryable, after := Classify(tt.err)
+			if retryable != tt.wantRetryable || after != tt.wantAfter {
+				t.Errorf("Classify() = (%v, %s), want (%v, %s)", retryable, after, tt.wantRetryable, tt.wantAfter)
+			}
+		})
+	}
+}
+
+func TestClassifyRetryAfterDate(t *testing.T) {
+	date := time.Now().Add(30 * time.Second).UTC().Format(http.TimeFormat)
+	retryable, after := Classify(HTTPError(response(http.StatusServiceUnavailable, date), nil))
+	if !retryable {
+		t.Fatal("Classify() not retryable, want retryable")
+	}
+	// HTTP dates have second precision, so the wait lands just under 30s
+	if after < 28*time.Second || after > 30*time.Second {
+		t.Errorf("Classify() after = %s, want about 30s", after)
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	tests := []struct {
+		name     string
+		policy   Policy
+		attempt  int
+		min, max time.Duration
+	}{
+		{"first retry", Policy{InitialDelay: time.Second, Multiplier: 2}, 1, time.Second, time.Second},
+		{"grows by the multiplier", Policy{InitialDelay: time.Second, Multiplier: 2}, 4, 8 * time.Second, 8 * time.Second},
+		{"multiplier below one stays flat", Policy{InitialDelay: time.Second, Multiplier: 0.5}, 5, time.Second, time.Second},
+		{"capped at the maximum", Policy{InitialDelay: time.Second, Multiplier: 2, MaxDelay: 5 * time.Second}, 10, 5 * time.Second, 5 * time.Second},
+		{"no cap", Policy{InitialDelay: time.Second, Multiplier: 2}, 11, 1024 * time.Second, 1024 * time.Second},
+		{"jitter spreads around the delay", Policy{InitialDelay: 10 * time.Second, Multiplier: 2, Jitter: 0.2}, 1, 8 * time.Second, 12 * time.Second},
+		{"jitter above one is treated as one", Policy{InitialDelay: 10 * time.Second, Multiplier: 2, Jitter: 3}, 1, 0, 20 * time.Second},
+		{"jitter never exceeds the cap", Policy{InitialDelay: time.Second, Multiplier: 2, MaxDelay: 5 * time.Second, Jitter: 0.5}, 10, 2500 * time.Millisecond, 5 * time.Second},
+		{"default policy", DefaultPolicy(), 8, 36 * time.Second, 45 * time.Second},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for range 100 {
+				if got := tt.policy.backoff(tt.attempt); got < tt.min || got > tt.max {
+					t.Fatalf("backoff(%d) = %s, want between %s and %s", tt.attempt, got, tt.min, tt.max)
+				}
+			}
+		})
+	}
+}
+
+func TestDoRetryAfter(t *testing.T) {
+	busy := HTTPError(response(http.StatusTooManyRequests, "3600"), nil)
+
+	t.Run("clamped to the maximum delay", func(t *testing.T) {
+		p := Policy{MaxAttempts: 2, InitialDelay: time.Millisecond, MaxDelay: 10 * time.Millisecond}
+		var delays []time.Duration
+		p.OnRetry = func(_ int, delay time.Duration, _ error) { delays = append(delays, delay) }
+
+		calls := 0
+		err := Do(context.Background(), p, func(context.Context) error {
+			calls++
+			if calls == 1 {
+				return busy
+			}
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("Do() error = %v", err)
+		}
+		if len(delays) != 1 || delays[0] != 10*time.Millisecond {
+			t.Errorf("retry delays = %v, want [10ms]", delays)
+		}
+	})
+
+	t.Run("fails fast past the deadline", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+		defer cancel()
+
+		calls := 0
+		start := time.Now()
+		err := Do(ctx, Policy{MaxAttempts: 5, InitialDelay: time.Millisecond}, func(context.Context) error {
+			calls++
+			return busy
+		})
+		if calls != 1 {
+			t.Errorf("fn called %d times, want 1", calls)
+		}
+		var e *Error
+		if !errors.As(err, &e) || e.StatusCode != http.StatusTooManyRequests {
+			t.Errorf("Do() error = %v, want the 429", err)
+		}
+		if elapsed := time.Since(start); elapsed > time.Second {
+			t.Errorf("Do() took %s, want an immediate failure", elapsed)
+		}
+	})
+}
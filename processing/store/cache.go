@@ -0,0 +1,167 @@
+package store
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"time"
+)
+
+// AnalysisEntry is a row of video_analysis_cache: the results of the
+// expensive analysis stages for one source file and analysis model
+type AnalysisEntry struct {
+	ID              int64
+	VideoPath       string
+	VideoHash       string
+	Duration        time.Duration
+	Transcript      string // JSON
+	SceneBoundaries string // JSON
+	ContentAnalysis string // JSON
+	AnalysisModel   string
+	CreatedAt       time.Time
+}
+
+// HashFile returns the hex SHA-256 of a file's contents, so a renamed or
+// copied source still hits the cache while an edited one doesn't
+func HashFile(ctx context.Context, path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to open %s for hashing: %w", path, err)
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	buf := make([]byte, 1<<20)
+	for {
+		if err := ctx.Err(); err != nil {
+			return "", err
+		}
+		n, err := f.Read(buf)
+		h.Write(buf[:n])
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return "", fmt.Errorf("failed to hash %s: %w", path, err)
+		}
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// LookupAnalysis returns the cached analysis for a content hash and model,
+// or ErrNotFound
+func (s *Store) LookupAnalysis(ctx context.Context, hash, model string) (*AnalysisEntry, error) {
+	var (
+		e                            AnalysisEntry
+		seconds                      float64
+		transcript, scenes, analysis sql.NullString
+		createdAt                    sql.NullTime
+	)
+	err := s.db.QueryRowContext(ctx, `
+		SELECT id, video_path, video_hash, duration_seconds, full_transcript, scene_boundaries,
+			content_analysis, analysis_model, created_at
+		FROM video_analysis_cache WHERE video_hash = ? AND analysis_model = ?`, hash, model).
+		Scan(&e.ID, &e.VideoPath, &e.VideoHash, &seconds, &transcript, &scenes, &analysis,
+			&e.AnalysisModel, &createdAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read analysis cache: %w", err)
+	}
+
+	e.Duration = time.Duration(seconds * float64(time.Second))
+	e.Transcript = transcript.String
+	e.SceneBoundaries = scenes.String
+	e.ContentAnalysis = analysis.String
+	e.CreatedAt = createdAt.Time
+	return &e, nil
+}
+
+// SaveAnalysis stores an analysis result, replacing any earlier entry for the
+// same content hash and model
+func (s *Store) SaveAnalysis(ctx context.Context, e *AnalysisEntry) error {
+	e.CreatedAt = time.Now()
+
+	_, err := s.db.ExecContext(ctx, `
+		INSERT INTO video_analysis_cache (video_path, video_hash, duration_seconds, full_transcript,
+			scene_boundaries, content_analysis, analysis_model, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT (video_hash, analysis_model) DO UPDATE SET
+			video_path = excluded.video_path,
+			duration_seconds = excluded.duration_seconds,
+			full_transcript = excluded.full_transcript,
+			scene_boundaries = excluded.scene_boundaries,
+			content_analysis = excluded.content_analysis,
+			created_at = excluded.created_at`,
+		e.VideoPath, e.VideoHash, e.Duration.Seconds(), nullString(e.Transcript),
+		nullString(e.SceneBoundaries), nullString(e.ContentAnalysis), e.AnalysisModel, e.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to save analysis cache: %w", err)
+	}
+	return nil
+}
+
+// PruneOptions selects which cache entries PruneAnalysis removes
+type PruneOptions struct {
+	OlderThan time.Duration // Remove entries created longer ago than this; 0 to ignore age
+	Missing   bool          // Remove entries whose source file no longer exists
+	All       bool          // Remove everything
+}
+
+// PruneAnalysis removes cache entries matching opts and returns how many were removed
+func (s *Store) PruneAnalysis(ctx context.Context, opts PruneOptions) (int64, error) {
+	if opts.All {
+		res, err := s.db.ExecContext(ctx, `DELETE FROM video_analysis_cache`)
+		if err != nil {
+			return 0, fmt.Errorf("failed to prune analysis cache: %w", err)
+		}
+		return res.RowsAffected()
+	}
+
+	rows, err := s.db.QueryContext(ctx, `SELECT id, video_path, created_at FROM video_analysis_cache`)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read analysis cache: %w", err)
+	}
+
+	var stale []int64
+	cutoff := time.Now().Add(-opts.OlderThan)
+	for rows.Next() {
+		var (
+			id        int64
+			path      string
+			createdAt sql.NullTime
+		)
+		if err := rows.Scan(&id, &path, &createdAt); err != nil {
+			rows.Close()
+			return 0, fmt.Errorf("failed to read analysis cache: %w", err)
+		}
+
+		expired := opts.OlderThan > 0 && createdAt.Time.Before(cutoff)
+		missing := false
+		if opts.Missing {
+			_, statErr := os.Stat(path)
+			missing = os.IsNotExist(statErr)
+		}
+		if expired || missing {
+			stale = append(stale, id)
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, fmt.Errorf("failed to read analysis cache: %w", err)
+	}
+
+	for _, id := range stale {
+		if _, err := s.db.ExecContext(ctx, `DELETE FROM video_analysis_cache WHERE id = ?`, id); err != nil {
+			return 0, fmt.Errorf("failed to prune analysis cache: %w", err)
+		}
+	}
+	return int64(len(stale)), nil
+}
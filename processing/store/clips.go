@@ -0,0 +1,154 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// Clip is a row of generated_clips
+type Clip struct {
+	ID               int64
+	JobID            int64
+	Name             string
+	FilePath         string
+	Index            int
+	Start            time.Duration
+	End              time.Duration
+	RelevanceScore   float64
+	ConfidenceScore  float64
+	Reason           string
+	Tags             string
+	TranscriptText   string
+	SceneDescription string
+	CaptionPaths     []string
+	ThumbnailPath    string
+	CreatedAt        time.Time
+}
+
+// Duration returns the clip's length
+func (c Clip) Duration() time.Duration {
+	return c.End - c.Start
+}
+
+// Files returns every file written for the clip: the clip itself, its
+// caption files and its thumbnail
+func (c Clip) Files() []string {
+	files := append([]string{c.FilePath}, c.CaptionPaths...)
+	if c.ThumbnailPath != "" {
+		files = append(files, c.ThumbnailPath)
+	}
+	return files
+}
+
+// SaveClip records a generated clip, replacing any earlier record of the same
+// clip index for the job so re-encoded clips don't duplicate
+func (s *Store) SaveClip(ctx context.Context, clip *Clip) error {
+	clip.CreatedAt = time.Now()
+
+	var captionPaths string
+	if len(clip.CaptionPaths) > 0 {
+		data, err := json.Marshal(clip.CaptionPaths)
+		if err != nil {
+			return fmt.Errorf("failed to encode caption paths of clip %d: %w", clip.Index, err)
+		}
+		captionPaths = string(data)
+	}
+
+	res, err := s.db.ExecContext(ctx, `
+		INSERT INTO generated_clips (job_id, clip_name, file_path, clip_index, start_time, end_time,
+			duration, relevance_score, confidence_score, reason, tags, transcript_text,
+			scene_description, caption_paths, thumbnail_path, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT (job_id, clip_index) DO UPDATE SET
+			clip_name = excluded.clip_name,
+			file_path = excluded.file_path,
+			start_time = excluded.start_time,
+			end_time = excluded.end_time,
+			duration = excluded.duration,
+			relevance_score = excluded.relevance_score,
+			confidence_score = excluded.confidence_score,
+			reason = excluded.reason,
+			tags = excluded.tags,
+			transcript_text = excluded.transcript_text,
+			scene_description = excluded.scene_description,
+			caption_paths = excluded.caption_paths,
+			thumbnail_path = excluded.thumbnail_path,
+			created_at = excluded.created_at`,
+		clip.JobID, clip.Name, clip.FilePath, clip.Index, clip.Start.Seconds(), clip.End.Seconds(),
+		clip.Duration().Seconds(), clip.RelevanceScore, clip.ConfidenceScore, nullString(clip.Reason),
+		nullString(clip.Tags), nullString(clip.TranscriptText), nullString(clip.SceneDescription),
+		nullString(captionPaths), nullString(clip.ThumbnailPath), clip.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to save clip %d of job %d: %w", clip.Index, clip.JobID, err)
+	}
+
+	if id, err := res.LastInsertId(); err == nil {
+		clip.ID = id
+	}
+	return nil
+}
+
+// ListClips returns a job's clips in index order
+func (s *Store) ListClips(ctx context.Context, jobID int64) ([]*Clip, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT id, job_id, clip_name, file_path, clip_index, start_time, end_time,
+			relevance_score, confidence_score, reason, tags, transcript_text,
+			scene_description, caption_paths, thumbnail_path, created_at
+		FROM generated_clips WHERE job_id = ? ORDER BY clip_index`, jobID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list clips of job %d: %w", jobID, err)
+	}
+	defer rows.Close()
+
+	var clips []*Clip
+	for rows.Next() {
+		var (
+			clip                                           Clip
+			start, end                                     float64
+			reason, tags, transcriptText, sceneDescription sql.NullString
+			captionPaths, thumbnailPath                    sql.NullString
+			createdAt                                      sql.NullTime
+		)
+		if err := rows.Scan(&clip.ID, &clip.JobID, &clip.Name, &clip.FilePath, &clip.Index,
+			&start, &end, &clip.RelevanceScore, &clip.ConfidenceScore, &reason, &tags,
+			&transcriptText, &sceneDescription, &captionPaths, &thumbnailPath, &createdAt); err != nil {
+			return nil, fmt.Errorf("failed to read clip: %w", err)
+		}
+		if captionPaths.Valid {
+			if err := json.Unmarshal([]byte(captionPaths.String), &clip.CaptionPaths); err != nil {
+				return nil, fmt.Errorf("failed to decode caption paths of clip %d: %w", clip.Index, err)
+			}
+		}
+
+		clip.Start = time.Duration(start * float64(time.Second))
+		clip.End = time.Duration(end * float64(time.Second))
+		clip.Reason = reason.String
+		clip.Tags = tags.String
+		clip.TranscriptText = transcriptText.String
+		clip.SceneDescription = sceneDescription.String
+		clip.ThumbnailPath = thumbnailPath.String
+		clip.CreatedAt = createdAt.Time
+		clips = append(clips, &clip)
+	}
+	return clips, rows.Err()
+}
+
+// FileInUse reports whether any job other than jobID recorded path as one of
+// its clip, caption or thumbnail files
+func (s *Store) FileInUse(ctx context.Context, path string, jobID int64) (bool, error) {
+	var inUse bool
+	err := s.db.QueryRowContext(ctx, `
+		SELECT EXISTS (
+			SELECT 1 FROM generated_clips
+			WHERE job_id != ? AND (file_path = ? OR thumbnail_path = ?
+				OR EXISTS (SELECT 1 FROM json_each(generated_clips.caption_paths) WHERE value = ?))
+		)`, jobID, path, path, path).Scan(&inUse)
+	if err != nil {
+		return false, fmt.Errorf("failed to look up users of %s: %w", path, err)
+	}
+	return inUse, nil
+}
@@ -0,0 +1,253 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Job statuses
+const (
+	StatusPending   = "pending"
+	StatusRunning   = "running"
+	StatusCompleted = "completed"
+	StatusFailed    = "failed"
+	StatusCancelled = "cancelled"
+)
+
+// ErrNotFound is returned when a job doesn't exist
+var ErrNotFound = errors.New("not found")
+
+// Job is a row of clip_jobs
+type Job struct {
+	ID                  int64
+	Name                string // The workspace ID, e.g. 20250808-142501-3f9a1c2b
+	Status              string
+	SourceVideo         string
+	OutputDirectory     string
+	UserPrompt          string
+	ProcessedPrompt     string
+	AIModel             string
+	ModelParameters     string // JSON
+	MinClipDuration     time.Duration
+	MaxClipDuration     time.Duration
+	MaxClips            int
+	ConfidenceThreshold float64
+	Progress            float64
+	ClipsGenerated      int
+	TotalClipsPlanned   int
+	CreatedAt           time.Time
+	StartedAt           time.Time // Zero until the job starts
+	CompletedAt         time.Time // Zero until the job finishes
+	ProcessingTime      time.Duration
+	ErrorMessage        string
+	SuccessRate         float64
+	TotalOutputDuration time.Duration
+}
+
+const jobColumns = `id, name, status, source_video, output_directory, user_prompt,
+	processed_prompt, ai_model, model_parameters, min_clip_duration, max_clip_duration,
+	max_clips, confidence_threshold, progress, clips_generated, total_clips_planned,
+	created_at, started_at, completed_at, processing_time_ms, error_message,
+	success_rate, total_output_duration`
+
+// CreateJob inserts a pending job and sets its ID and CreatedAt
+func (s *Store) CreateJob(ctx context.Context, job *Job) error {
+	if job.Status == "" {
+		job.Status = StatusPending
+	}
+	job.CreatedAt = time.Now()
+
+	res, err := s.db.ExecContext(ctx, `
+		INSERT INTO clip_jobs (name, status, source_video, output_directory, user_prompt,
+			processed_prompt, ai_model, model_parameters, min_clip_duration, max_clip_duration,
+			max_clips, confidence_threshold, progress, clips_generated, total_clips_planned, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, 0, 0, 0, ?)`,
+		job.Name, job.Status, job.SourceVideo, job.OutputDirectory, job.UserPrompt,
+		nullString(job.ProcessedPrompt), job.AIModel, nullString(job.ModelParameters),
+		job.MinClipDuration.Milliseconds(), job.MaxClipDuration.Milliseconds(),
+		job.MaxClips, job.ConfidenceThreshold, job.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create job: %w", err)
+	}
+
+	job.ID, err = res.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("failed to read job id: %w", err)
+	}
+	return nil
+}
+
+// StartJob marks a job as running. Starting a resumed job resets its
+// timings and error but keeps its checkpoint and clips.
+func (s *Store) StartJob(ctx context.Context, id int64) error {
+	return s.updateJob(ctx, id, `UPDATE clip_jobs SET status = ?, started_at = ?, completed_at = NULL,
+		error_message = NULL WHERE id = ?`, StatusRunning, time.Now(), id)
+}
+
+// UpdateProgress records how far through the pipeline a job is, from 0 to 1
+func (s *Store) UpdateProgress(ctx context.Context, id int64, progress float64) error {
+	return s.updateJob(ctx, id, `UPDATE clip_jobs SET progress = ? WHERE id = ?`, progress, id)
+}
+
+// SetClipsPlanned records how many clips the job selected for extraction
+func (s *Store) SetClipsPlanned(ctx context.Context, id int64, planned int) error {
+	return s.updateJob(ctx, id, `UPDATE clip_jobs SET total_clips_planned = ? WHERE id = ?`, planned, id)
+}
+
+// FinishJob records the outcome of a job. A nil runErr completes it; a
+// context.Canceled error marks it cancelled and anything else failed.
+// Clip counts and totals are derived from the job's recorded clips.
+func (s *Store) FinishJob(ctx context.Context, id int64, runErr error) error {
+	status, message := outcome(runErr)
+
+	job, err := s.GetJob(ctx, id)
+	if err != nil {
+		return err
+	}
+	started := job.StartedAt
+	if started.IsZero() {
+		started = job.CreatedAt
+	}
+	now := time.Now()
+
+	return s.updateJob(ctx, id, `
+		UPDATE clip_jobs SET
+			status = ?,
+			error_message = ?,
+			completed_at = ?,
+			progress = CASE WHEN ? THEN 1 ELSE progress END,
+			processing_time_ms = ?,
+			clips_generated = (SELECT COUNT(*) FROM generated_clips WHERE job_id = clip_jobs.id),
+			total_output_duration = (SELECT CAST(COALESCE(SUM(duration), 0) * 1000 AS INTEGER) FROM generated_clips WHERE job_id = clip_jobs.id),
+			success_rate = CASE WHEN total_clips_planned > 0
+				THEN (SELECT COUNT(*) FROM generated_clips WHERE job_id = clip_jobs.id) * 1.0 / total_clips_planned
+				ELSE NULL END
+		WHERE id = ?`,
+		status, nullString(message), now, runErr == nil, now.Sub(started).Milliseconds(), id)
+}
+
+// outcome maps an error to a status and error message
+func outcome(err error) (status, message string) {
+	switch {
+	case err == nil:
+		return StatusCompleted, ""
+	case errors.Is(err, context.Canceled):
+		return StatusCancelled, err.Error()
+	default:
+		return StatusFailed, err.Error()
+	}
+}
+
+// GetJob loads a job by ID
+func (s *Store) GetJob(ctx context.Context, id int64) (*Job, error) {
+	row := s.db.QueryRowContext(ctx, `SELECT `+jobColumns+` FROM clip_jobs WHERE id = ?`, id)
+	job, err := scanJob(row)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load job %d: %w", id, err)
+	}
+	return job, nil
+}
+
+// GetJobByName loads a job by its workspace ID
+func (s *Store) GetJobByName(ctx context.Context, name string) (*Job, error) {
+	row := s.db.QueryRowContext(ctx, `SELECT `+jobColumns+` FROM clip_jobs WHERE name = ?`, name)
+	job, err := scanJob(row)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load job %s: %w", name, err)
+	}
+	return job, nil
+}
+
+// ListJobs returns the most recent jobs first. A limit of 0 returns all jobs.
+func (s *Store) ListJobs(ctx context.Context, limit int) ([]*Job, error) {
+	query := `SELECT ` + jobColumns + ` FROM clip_jobs ORDER BY id DESC`
+	if limit > 0 {
+		query += fmt.Sprintf(" LIMIT %d", limit)
+	}
+
+	rows, err := s.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list jobs: %w", err)
+	}
+	defer rows.Close()
+
+	var jobs []*Job
+	for rows.Next() {
+		job, err := scanJob(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read job: %w", err)
+		}
+		jobs = append(jobs, job)
+	}
+	return jobs, rows.Err()
+}
+
+// DeleteJob removes a job and, by cascade, its clip records. Files on disk are untouched.
+func (s *Store) DeleteJob(ctx context.Context, id int64) error {
+	return s.updateJob(ctx, id, `DELETE FROM clip_jobs WHERE id = ?`, id)
+}
+
+// updateJob runs a statement that must affect exactly the given job
+func (s *Store) updateJob(ctx context.Context, id int64, query string, args ...any) error {
+	res, err := s.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("failed to update job %d: %w", id, err)
+	}
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return fmt.Errorf("job %d: %w", id, ErrNotFound)
+	}
+	return nil
+}
+
+type scanner interface {
+	Scan(dest ...any) error
+}
+
+func scanJob(row scanner) (*Job, error) {
+	var (
+		job                                            Job
+		processedPrompt, modelParameters, errorMessage sql.NullString
+		minClip, maxClip, processingTime, totalOutput  sql.NullInt64
+		maxClips, clipsGenerated, clipsPlanned         sql.NullInt64
+		confidenceThreshold, progress, successRate     sql.NullFloat64
+		createdAt, startedAt, completedAt              sql.NullTime
+	)
+
+	err := row.Scan(&job.ID, &job.Name, &job.Status, &job.SourceVideo, &job.OutputDirectory,
+		&job.UserPrompt, &processedPrompt, &job.AIModel, &modelParameters, &minClip, &maxClip,
+		&maxClips, &confidenceThreshold, &progress, &clipsGenerated, &clipsPlanned,
+		&createdAt, &startedAt, &completedAt, &processingTime, &errorMessage,
+		&successRate, &totalOutput)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	job.ProcessedPrompt = processedPrompt.String
+	job.ModelParameters = modelParameters.String
+	job.ErrorMessage = errorMessage.String
+	job.MinClipDuration = time.Duration(minClip.Int64) * time.Millisecond
+	job.MaxClipDuration = time.Duration(maxClip.Int64) * time.Millisecond
+	job.ProcessingTime = time.Duration(processingTime.Int64) * time.Millisecond
+	job.TotalOutputDuration = time.Duration(totalOutput.Int64) * time.Millisecond
+	job.MaxClips = int(maxClips.Int64)
+	job.ClipsGenerated = int(clipsGenerated.Int64)
+	job.TotalClipsPlanned = int(clipsPlanned.Int64)
+	job.ConfidenceThreshold = confidenceThreshold.Float64
+	job.Progress = progress.Float64
+	job.SuccessRate = successRate.Float64
+	job.CreatedAt = createdAt.Time
+	job.StartedAt = startedAt.Time
+	job.CompletedAt = completedAt.Time
+	return &job, nil
+}
+
+func nullString(s string) sql.NullString {
+	return sql.NullString{String: s, Valid: s != ""}
+}
@@ -0,0 +1,103 @@
+package store
+
+// migrations are applied in order; a database's PRAGMA user_version is the
+// number already applied. Never edit a released migration, append a new one.
+var migrations = []string{
+	// 1: jobs, clips and the analysis cache
+	`
+CREATE TABLE clip_jobs (
+	id                    INTEGER PRIMARY KEY AUTOINCREMENT,
+	name                  VARCHAR NOT NULL UNIQUE,
+	status                VARCHAR NOT NULL,
+	source_video          VARCHAR NOT NULL,
+	output_directory      VARCHAR NOT NULL,
+	user_prompt           TEXT NOT NULL,
+	processed_prompt      TEXT,
+	ai_model              VARCHAR NOT NULL,
+	model_parameters      TEXT,
+	min_clip_duration     INTEGER, -- milliseconds
+	max_clip_duration     INTEGER, -- milliseconds
+	max_clips             INTEGER,
+	confidence_threshold  DECIMAL,
+	progress              DECIMAL,  -- 0 to 1
+	clips_generated       INTEGER,
+	total_clips_planned   INTEGER,
+	created_at            DATETIME,
+	started_at            DATETIME,
+	completed_at          DATETIME,
+	processing_time_ms    INTEGER,
+	error_message         TEXT,
+	success_rate          DECIMAL,
+	total_output_duration INTEGER  -- milliseconds
+);
+
+CREATE TABLE generated_clips (
+	id                INTEGER PRIMARY KEY AUTOINCREMENT,
+	job_id            INTEGER NOT NULL REFERENCES clip_jobs(id) ON DELETE CASCADE,
+	clip_name         VARCHAR NOT NULL,
+	file_path         VARCHAR NOT NULL,
+	clip_index        INTEGER NOT NULL,
+	start_time        DECIMAL NOT NULL, -- seconds
+	end_time          DECIMAL NOT NULL, -- seconds
+	duration          DECIMAL NOT NULL, -- seconds
+	relevance_score   DECIMAL NOT NULL,
+	confidence_score  DECIMAL NOT NULL,
+	reason            TEXT,
+	tags              TEXT,
+	transcript_text   TEXT,
+	scene_description TEXT,
+	created_at        DATETIME,
+	UNIQUE (job_id, clip_index)
+);
+
+CREATE INDEX generated_clips_job_id ON generated_clips(job_id);
+
+CREATE TABLE video_analysis_cache (
+	id               INTEGER PRIMARY KEY AUTOINCREMENT,
+	video_path       VARCHAR NOT NULL,
+	video_hash       VARCHAR NOT NULL,
+	duration_seconds DECIMAL NOT NULL,
+	full_transcript  TEXT,
+	scene_boundaries TEXT,
+	content_analysis TEXT,
+	analysis_model   VARCHAR NOT NULL,
+	created_at       DATETIME
+);
+
+CREATE INDEX video_analysis_cache_hash ON video_analysis_cache(video_hash, analysis_model);
+`,
+
+	// 2: one cache entry per source and analysis model, keeping the newest
+	// of any duplicates written before the key was unique
+	`
+DELETE FROM video_analysis_cache WHERE rowid NOT IN (
+	SELECT MAX(rowid) FROM video_analysis_cache GROUP BY video_hash, analysis_model
+);
+DROP INDEX video_analysis_cache_hash;
+CREATE UNIQUE INDEX video_analysis_cache_key ON video_analysis_cache(video_hash, analysis_model);
+`,
+
+	// 3: per-stage progress and the latest resumable checkpoint of each job
+	`
+ALTER TABLE clip_jobs ADD COLUMN checkpoint TEXT;
+ALTER TABLE clip_jobs ADD COLUMN checkpoint_stage INTEGER;
+
+CREATE TABLE job_stages (
+	job_id        INTEGER NOT NULL REFERENCES clip_jobs(id) ON DELETE CASCADE,
+	stage_index   INTEGER NOT NULL,
+	name          VARCHAR NOT NULL,
+	status        VARCHAR NOT NULL,
+	started_at    DATETIME,
+	finished_at   DATETIME,
+	duration_ms   INTEGER,
+	error_message TEXT,
+	PRIMARY KEY (job_id, stage_index)
+);
+`,
+
+	// 4: caption and thumbnail files written alongside each clip
+	`
+ALTER TABLE generated_clips ADD COLUMN caption_paths TEXT; -- JSON array
+ALTER TABLE generated_clips ADD COLUMN thumbnail_path VARCHAR;
+`,
+}
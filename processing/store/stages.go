@@ -0,0 +1,112 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Stage is a row of job_stages: one pipeline stage of a job
+type Stage struct {
+	JobID        int64
+	Index        int
+	Name         string
+	Status       string
+	StartedAt    time.Time
+	FinishedAt   time.Time // Zero while running
+	Duration     time.Duration
+	ErrorMessage string
+}
+
+// StartStage records that a stage began, replacing the record of any earlier attempt
+func (s *Store) StartStage(ctx context.Context, jobID int64, index int, name string) error {
+	_, err := s.db.ExecContext(ctx, `
+		INSERT INTO job_stages (job_id, stage_index, name, status, started_at)
+		VALUES (?, ?, ?, ?, ?)
+		ON CONFLICT (job_id, stage_index) DO UPDATE SET
+			name = excluded.name,
+			status = excluded.status,
+			started_at = excluded.started_at,
+			finished_at = NULL,
+			duration_ms = NULL,
+			error_message = NULL`,
+		jobID, index, name, StatusRunning, time.Now())
+	if err != nil {
+		return fmt.Errorf("failed to record start of stage %q: %w", name, err)
+	}
+	return nil
+}
+
+// FinishStage records a stage's outcome the same way FinishJob does for jobs
+func (s *Store) FinishStage(ctx context.Context, jobID int64, index int, elapsed time.Duration, stageErr error) error {
+	status, message := outcome(stageErr)
+	_, err := s.db.ExecContext(ctx, `
+		UPDATE job_stages SET status = ?, finished_at = ?, duration_ms = ?, error_message = ?
+		WHERE job_id = ? AND stage_index = ?`,
+		status, time.Now(), elapsed.Milliseconds(), nullString(message), jobID, index)
+	if err != nil {
+		return fmt.Errorf("failed to record end of stage %d: %w", index, err)
+	}
+	return nil
+}
+
+// ListStages returns a job's stages in pipeline order
+func (s *Store) ListStages(ctx context.Context, jobID int64) ([]*Stage, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT job_id, stage_index, name, status, started_at, finished_at, duration_ms, error_message
+		FROM job_stages WHERE job_id = ? ORDER BY stage_index`, jobID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list stages of job %d: %w", jobID, err)
+	}
+	defer rows.Close()
+
+	var stages []*Stage
+	for rows.Next() {
+		var (
+			stage                 Stage
+			startedAt, finishedAt sql.NullTime
+			duration              sql.NullInt64
+			message               sql.NullString
+		)
+		if err := rows.Scan(&stage.JobID, &stage.Index, &stage.Name, &stage.Status,
+			&startedAt, &finishedAt, &duration, &message); err != nil {
+			return nil, fmt.Errorf("failed to read stage: %w", err)
+		}
+		stage.StartedAt = startedAt.Time
+		stage.FinishedAt = finishedAt.Time
+		stage.Duration = time.Duration(duration.Int64) * time.Millisecond
+		stage.ErrorMessage = message.String
+		stages = append(stages, &stage)
+	}
+	return stages, rows.Err()
+}
+
+// SaveCheckpoint stores the job's state after a completed stage. Only the
+// latest checkpoint is kept since it includes everything earlier stages produced.
+func (s *Store) SaveCheckpoint(ctx context.Context, jobID int64, stageIndex int, data []byte) error {
+	return s.updateJob(ctx, jobID, `UPDATE clip_jobs SET checkpoint = ?, checkpoint_stage = ? WHERE id = ?`,
+		string(data), stageIndex, jobID)
+}
+
+// LoadCheckpoint returns a job's latest checkpoint and the index of the stage
+// it was taken after, or -1 and no data if no stage has completed
+func (s *Store) LoadCheckpoint(ctx context.Context, jobID int64) (int, []byte, error) {
+	var (
+		stage sql.NullInt64
+		data  sql.NullString
+	)
+	err := s.db.QueryRowContext(ctx, `SELECT checkpoint_stage, checkpoint FROM clip_jobs WHERE id = ?`, jobID).
+		Scan(&stage, &data)
+	if errors.Is(err, sql.ErrNoRows) {
+		return -1, nil, fmt.Errorf("job %d: %w", jobID, ErrNotFound)
+	}
+	if err != nil {
+		return -1, nil, fmt.Errorf("failed to load checkpoint of job %d: %w", jobID, err)
+	}
+	if !stage.Valid || !data.Valid {
+		return -1, nil, nil
+	}
+	return int(stage.Int64), []byte(data.String), nil
+}
@@ -0,0 +1,126 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/spf13/viper"
+)
+
+// DefaultFile is the database file name used when db-path isn't configured
+const DefaultFile = "ai-editor.db"
+
+// Store is the SQLite database recording processing jobs, their clips and
+// cached analysis results
+type Store struct {
+	db   *sql.DB
+	Path string
+}
+
+// DefaultPath returns the db-path config value, or ~/.ai-editor/ai-editor.db
+func DefaultPath() string {
+	if path := viper.GetString("db-path"); path != "" {
+		return path
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return DefaultFile
+	}
+	return filepath.Join(home, ".ai-editor", DefaultFile)
+}
+
+// Open opens or creates the database at path and applies any pending migrations
+func Open(path string) (*Store, error) {
+	if dir := filepath.Dir(path); dir != "" {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return nil, fmt.Errorf("failed to create database directory: %w", err)
+		}
+	}
+
+	// A busy timeout lets concurrent runs share the file instead of failing on lock
+	db, err := sql.Open("sqlite3", path+"?_foreign_keys=on&_busy_timeout=5000")
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database: %w", err)
+	}
+
+	s := &Store{db: db, Path: path}
+	if err := s.migrate(context.Background()); err != nil {
+		db.Close()
+		return nil, err
+	}
+	return s, nil
+}
+
+// OpenReadOnly opens an existing database without creating it or applying
+// migrations, for inspecting it without side effects
+func OpenReadOnly(path string) (*Store, error) {
+	if _, err := os.Stat(path); err != nil {
+		return nil, fmt.Errorf("failed to open database: %w", err)
+	}
+
+	db, err := sql.Open("sqlite3", "file:"+path+"?mode=ro&_busy_timeout=5000")
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database: %w", err)
+	}
+	// sql.Open is lazy; connect now so a driver built without cgo fails here
+	if err := db.Ping(); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to open database: %w", err)
+	}
+	return &Store{db: db, Path: path}, nil
+}
+
+// SchemaLatest returns the schema version this build migrates databases to
+func SchemaLatest() int {
+	return len(migrations)
+}
+
+// Close closes the database
+func (s *Store) Close() error {
+	return s.db.Close()
+}
+
+// SchemaVersion returns the number of migrations applied to the database
+func (s *Store) SchemaVersion(ctx context.Context) (int, error) {
+	var version int
+	if err := s.db.QueryRowContext(ctx, "PRAGMA user_version").Scan(&version); err != nil {
+		return 0, fmt.Errorf("failed to read schema version: %w", err)
+	}
+	return version, nil
+}
+
+// migrate applies every migration newer than the database's user_version,
+// each in its own transaction
+func (s *Store) migrate(ctx context.Context) error {
+	current, err := s.SchemaVersion(ctx)
+	if err != nil {
+		return err
+	}
+	if current > len(migrations) {
+		return fmt.Errorf("database %s has schema version %d, newer than this build supports (%d)", s.Path, current, len(migrations))
+	}
+
+	for version := current + 1; version <= len(migrations); version++ {
+		tx, err := s.db.BeginTx(ctx, nil)
+		if err != nil {
+			return fmt.Errorf("failed to begin migration %d: %w", version, err)
+		}
+		if _, err := tx.ExecContext(ctx, migrations[version-1]); err != nil {
+			tx.Rollback()
+			return fmt.Errorf("failed to apply migration %d: %w", version, err)
+		}
+		// PRAGMA doesn't accept bind parameters
+		if _, err := tx.ExecContext(ctx, fmt.Sprintf("PRAGMA user_version = %d", version)); err != nil {
+			tx.Rollback()
+			return fmt.Errorf("failed to record migration %d: %w", version, err)
+		}
+		if err := tx.Commit(); err != nil {
+			return fmt.Errorf("failed to commit migration %d: %w", version, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,347 @@
+package toolchain
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+// MinMajorVersion is the oldest ffmpeg release the editor is tested against
+const MinMajorVersion = 4
+
+// Environment variables that override binary discovery
+const (
+	FFmpegEnv  = "AI_EDITOR_FFMPEG"
+	FFprobeEnv = "AI_EDITOR_FFPROBE"
+)
+
+// DefaultEncoders are the encoders every pipeline run depends on
+var DefaultEncoders = []string{"pcm_s16le", "libx264", "aac"}
+
+// DefaultFilters are the filters every pipeline run depends on
+var DefaultFilters = []string{"scale", "crop"}
+
+// Binary is a resolved ffmpeg-family executable
+type Binary struct {
+	Name    string
+	Path    string
+	Version string
+	Major   int
+	Minor   int
+	Source  string // config, env, path or install location
+}
+
+// Toolchain holds the resolved ffmpeg and ffprobe binaries and their capabilities
+type Toolchain struct {
+	FFmpeg   Binary
+	FFprobe  Binary
+	encoders map[string]bool
+	filters  map[string]bool
+}
+
+// Options controls where Discover looks for binaries
+type Options struct {
+	FFmpegPath  string
+	FFprobePath string
+	Timeout     time.Duration
+}
+
+// DefaultOptions builds discovery options from the ffmpeg-path and ffprobe-path config keys
+func DefaultOptions() Options {
+	return Options{
+		FFmpegPath:  viper.GetString("ffmpeg-path"),
+		FFprobePath: viper.GetString("ffprobe-path"),
+		Timeout:     10 * time.Second,
+	}
+}
+
+// DiscoveryError explains why a binary could not be resolved
+type DiscoveryError struct {
+	Binary string
+	Tried  []string
+	Reason string
+}
+
+func (e *DiscoveryError) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s not usable: %s", e.Binary, e.Reason)
+	if len(e.Tried) > 0 {
+		fmt.Fprintf(&b, "\n  looked in:")
+		for _, t := range e.Tried {
+			fmt.Fprintf(&b, "\n    - %s", t)
+		}
+	}
+	fmt.Fprintf(&b, "\n  hint: %s", InstallHint(e.Binary))
+	return b.String()
+}
+
+// Discover resolves ffmpeg and ffprobe and reads their versions and capabilities.
+// Lookup order is: config key, environment variable, $PATH, common install locations.
+func Discover(opts Options) (*Toolchain, error) {
+	if opts.Timeout <= 0 {
+		opts.Timeout = 10 * time.Second
+	}
+
+	ffmpegBin, err := resolve("ffmpeg", opts.FFmpegPath, FFmpegEnv, "", opts.Timeout)
+	if err != nil {
+		return nil, err
+	}
+
+	// ffprobe almost always ships next to ffmpeg, so try that directory first
+	ffprobeBin, err := resolve("ffprobe", opts.FFprobePath, FFprobeEnv, filepath.Dir(ffmpegBin.Path), opts.Timeout)
+	if err != nil {
+		return nil, err
+	}
+
+	tc := &Toolchain{FFmpeg: ffmpegBin, FFprobe: ffprobeBin}
+
+	ctx, cancel := context.WithTimeout(context.Background(), opts.Timeout)
+	defer cancel()
+
+	out, err := runQuiet(ctx, ffmpegBin.Path, "-hide_banner", "-encoders")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list ffmpeg encoders: %w", err)
+	}
+	tc.encoders = parseCapabilityList(out)
+
+	out, err = runQuiet(ctx, ffmpegBin.Path, "-hide_banner", "-filters")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list ffmpeg filters: %w", err)
+	}
+	tc.filters = parseCapabilityList(out)
+
+	return tc, nil
+}
+
+// HasEncoder reports whether ffmpeg was built with the named encoder
+func (t *Toolchain) HasEncoder(name string) bool {
+	return t.encoders[name]
+}
+
+// HasFilter reports whether ffmpeg was built with the named filter
+func (t *Toolchain) HasFilter(name string) bool {
+	return t.filters[name]
+}
+
+// Verify checks that all of the given encoders and filters are available
+func (t *Toolchain) Verify(encoders, filters []string) error {
+	var missing []string
+	for _, e := range encoders {
+		if !t.HasEncoder(e) {
+			missing = append(missing, "encoder "+e)
+		}
+	}
+	for _, f := range filters {
+		if !t.HasFilter(f) {
+			missing = append(missing, "filter "+f)
+		}
+	}
+	if len(missing) > 0 {
+		return &DiscoveryError{
+			Binary: "ffmpeg",
+			Tried:  []string{t.FFmpeg.Path},
+			Reason: "missing " + strings.Join(missing, ", "),
+		}
+	}
+	return nil
+}
+
+// InstallHint returns a platform specific suggestion for installing ffmpeg
+func InstallHint(binary string) string {
+	switch runtime.GOOS {
+	case "darwin":
+		return "install with `brew install ffmpeg` or set " + configKey(binary)
+	case "windows":
+		return "install with `winget install ffmpeg` or set " + configKey(binary)
+	default:
+		return "install with your package manager (e.g. `apt install ffmpeg`) or set " + configKey(binary)
+	}
+}
+
+func configKey(binary string) string {
+	env := FFmpegEnv
+	if binary == "ffprobe" {
+		env = FFprobeEnv
+	}
+	return fmt.Sprintf("`ai-editor config set %s-path <path>` or $%s", binary, env)
+}
+
+func resolve(name, configured, envVar, siblingDir string, timeout time.Duration) (Binary, error) {
+	var tried []string
+
+	try := func(path, source string) (Binary, bool, error) {
+		tried = append(tried, fmt.Sprintf("%s (%s)", path, source))
+		if !isExecutable(path) {
+			return Binary{}, false, nil
+		}
+		bin, err := inspect(name, path, source, timeout)
+		if err != nil {
+			return Binary{}, false, err
+		}
+		return bin, true, nil
+	}
+
+	// Explicit locations are authoritative: if they're set but broken we fail
+	// rather than silently falling back to something else on the system
+	explicit := []struct{ path, source string }{
+		{configured, "config"},
+		{os.Getenv(envVar), "env " + envVar},
+	}
+	for _, e := range explicit {
+		if e.path == "" {
+			continue
+		}
+		bin, ok, err := try(e.path, e.source)
+		if err != nil {
+			return Binary{}, &DiscoveryError{Binary: name, Tried: tried, Reason: err.Error()}
+		}
+		if !ok {
+			return Binary{}, &DiscoveryError{Binary: name, Tried: tried, Reason: "configured path is not an executable file"}
+		}
+		return bin, nil
+	}
+
+	var candidates []struct{ path, source string }
+	if siblingDir != "" {
+		candidates = append(candidates, struct{ path, source string }{filepath.Join(siblingDir, exeName(name)), "next to ffmpeg"})
+	}
+	if p, err := exec.LookPath(name); err == nil {
+		candidates = append(candidates, struct{ path, source string }{p, "PATH"})
+	} else {
+		tried = append(tried, "$PATH")
+	}
+	for _, dir := range commonLocations() {
+		candidates = append(candidates, struct{ path, source string }{filepath.Join(dir, exeName(name)), "install location"})
+	}
+
+	var lastErr error
+	for _, c := range candidates {
+		bin, ok, err := try(c.path, c.source)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		if ok {
+			return bin, nil
+		}
+	}
+
+	reason := "not found"
+	if lastErr != nil {
+		reason = lastErr.Error()
+	}
+	return Binary{}, &DiscoveryError{Binary: name, Tried: tried, Reason: reason}
+}
+
+var versionPattern = regexp.MustCompile(`version\s+n?(\d+)\.(\d+)`)
+
+func inspect(name, path, source string, timeout time.Duration) (Binary, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	out, err := runQuiet(ctx, path, "-version")
+	if err != nil {
+		return Binary{}, fmt.Errorf("failed to run %s -version: %w", path, err)
+	}
+
+	firstLine, _, _ := strings.Cut(out, "\n")
+	bin := Binary{Name: name, Path: path, Source: source}
+
+	if fields := strings.Fields(firstLine); len(fields) >= 3 && fields[1] == "version" {
+		bin.Version = fields[2]
+	}
+
+	// Git builds report versions like "N-113000-g1234abcd" which we can't compare,
+	// so only enforce the minimum when a release number is present
+	if m := versionPattern.FindStringSubmatch(firstLine); m != nil {
+		bin.Major, _ = strconv.Atoi(m[1])
+		bin.Minor, _ = strconv.Atoi(m[2])
+		if bin.Major < MinMajorVersion {
+			return Binary{}, fmt.Errorf("%s %s is too old (need %d.0 or newer)", name, bin.Version, MinMajorVersion)
+		}
+	}
+
+	return bin, nil
+}
+
+// parseCapabilityList extracts names from `ffmpeg -encoders` / `ffmpeg -filters` output,
+// where each entry is a flags column followed by the name
+func parseCapabilityList(out string) map[string]bool {
+	caps := make(map[string]bool)
+	scanner := bufio.NewScanner(strings.NewReader(out))
+	inList := false
+	for scanner.Scan() {
+		line := scanner.Text()
+		if strings.HasPrefix(strings.TrimSpace(line), "---") {
+			inList = true
+			continue
+		}
+		fields := strings.Fields(line)
+		if !inList || len(fields) < 2 {
+			continue
+		}
+		caps[fields[1]] = true
+	}
+	return caps
+}
+
+func runQuiet(ctx context.Context, path string, args ...string) (string, error) {
+	cmd := exec.CommandContext(ctx, path, args...)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("%w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+	return stdout.String(), nil
+}
+
+func isExecutable(path string) bool {
+	info, err := os.Stat(path)
+	if err != nil || info.IsDir() {
+		return false
+	}
+	if runtime.GOOS == "windows" {
+		return true
+	}
+	return info.Mode()&0111 != 0
+}
+
+func exeName(name string) string {
+	if runtime.GOOS == "windows" {
+		return name + ".exe"
+	}
+	return name
+}
+
+func commonLocations() []string {
+	switch runtime.GOOS {
+	case "windows":
+		dirs := []string{`C:\ffmpeg\bin`}
+		if pf := os.Getenv("ProgramFiles"); pf != "" {
+			dirs = append(dirs, filepath.Join(pf, "ffmpeg", "bin"))
+		}
+		if local := os.Getenv("LOCALAPPDATA"); local != "" {
+			dirs = append(dirs, filepath.Join(local, "Microsoft", "WinGet", "Links"))
+		}
+		if profile := os.Getenv("USERPROFILE"); profile != "" {
+			dirs = append(dirs, filepath.Join(profile, "scoop", "shims"))
+		}
+		return dirs
+	case "darwin":
+		return []string{"/opt/homebrew/bin", "/usr/local/bin", "/opt/local/bin"}
+	default:
+		return []string{"/usr/bin", "/usr/local/bin", "/snap/bin", "/opt/ffmpeg/bin"}
+	}
+}
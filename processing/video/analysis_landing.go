@@ -1,110 +1,91 @@
 package video
 
 import (
+	"context"
 	"fmt"
-	"log"
-	"os"
 
-	ffmpeg "github.com/u2takey/ffmpeg-go"
+	"ai-video-editor/processing/workspace"
 )
 
-func Analyze(inputPath string) {
+// AnalyzeOptions holds every input Analyze needs; nothing is read from the
+// process environment
+type AnalyzeOptions struct {
+	FFmpegPath  string
+	FFprobePath string
+	TempDir     string
+	Workspace   *workspace.Workspace // Optional; overrides TempDir and tracks every extracted file
+	SkipAudio   bool
+	SkipVideo   bool
+}
 
-	// Set FFprobe path using environment variable (correct approach for u2takey/ffmpeg-go)
-	os.Setenv("FFPROBE_PATH", "C:\\ffmpeg\\bin\\ffprobe.exe")
-	
-	// Also ensure PATH includes FFmpeg directory
-	currentPath := os.Getenv("PATH")
-	os.Setenv("PATH", "C:\\ffmpeg\\bin;"+currentPath)
-	
-	// Check if file path was provided as argument
-	if len(os.Args) < 2 {
-		log.Fatal("Usage: go run main.go <path-to-video-file>")
-	}
+// AnalysisResult is the output of Analyze. Callers own the extracted files
+// and should call Cleanup when they are done with them.
+type AnalysisResult struct {
+	Info      MediaInfo
+	AudioPath string
+	VideoPath string
 
-	filePath := os.Args[1]
+	workspace *workspace.Workspace
+}
 
-	fmt.Printf("📹 Analyzing video: %s\n\n", filePath)
+// Analyze probes a video file and extracts its audio and video tracks into
+// the temp directory. Cancelling ctx stops any running ffmpeg/ffprobe process.
+func Analyze(ctx context.Context, inputPath string, opts AnalyzeOptions) (*AnalysisResult, error) {
+	if opts.FFmpegPath == "" {
+		opts.FFmpegPath = "ffmpeg"
+	}
+	if opts.FFprobePath == "" {
+		opts.FFprobePath = "ffprobe"
+	}
+	if opts.Workspace != nil {
+		opts.TempDir = opts.Workspace.Dir
+	}
+	if opts.TempDir == "" {
+		opts.TempDir = "./temp"
+	}
 
-	// Use ffprobe to get video metadata
-	_, err := ffmpeg.Probe(inputPath)
+	info, err := Probe(ctx, opts.FFprobePath, inputPath)
 	if err != nil {
-		log.Fatalf("❌ Error probing video file: %v", err)
+		return nil, fmt.Errorf("error probing video file: %w", err)
 	}
 
-	ExtractAudio(inputPath)
-	ExtractVideo(inputPath)
-
-	/*
-	fmt.Println("✅ Successfully probed video file!")
+	result := &AnalysisResult{Info: info, workspace: opts.Workspace}
 
-	// Parse the JSON response
-	type ProbeData struct {
-		Format struct {
-			Filename       string `json:"filename"`
-			FormatName     string `json:"format_name"`
-			FormatLongName string `json:"format_long_name"`
-			Duration       string `json:"duration"`
-			Size           string `json:"size"`
-			BitRate        string `json:"bit_rate"`
-		} `json:"format"`
-		Streams []struct {
-			Index              int    `json:"index"`
-			CodecName          string `json:"codec_name"`
-			CodecLongName      string `json:"codec_long_name"`
-			CodecType          string `json:"codec_type"`
-			Width              int    `json:"width"`
-			Height             int    `json:"height"`
-			PixelFormat        string `json:"pix_fmt"`
-			Duration           string `json:"duration"`
-			BitRate            string `json:"bit_rate"`
-			SampleRate         string `json:"sample_rate"`
-			Channels           int    `json:"channels"`
-		} `json:"streams"`
-	}
+	if !opts.SkipAudio && info.HasAudio() {
+		extractor := NewAudioExtractor(opts.TempDir)
+		extractor.FFmpegPath = opts.FFmpegPath
+		extractor.Workspace = opts.Workspace
 
-	var probeData ProbeData
-	err = json.Unmarshal([]byte(data), &probeData)
-	if err != nil {
-		log.Fatalf("❌ Error parsing probe data: %v", err)
+		result.AudioPath, err = extractor.ExtractAudioPath(ctx, inputPath)
+		if err != nil {
+			result.Cleanup()
+			return nil, err
+		}
 	}
 
-	// Print basic file information
-	fmt.Println("📊 File Information:")
-	fmt.Printf("   Filename: %s\n", probeData.Format.Filename)
-	fmt.Printf("   Format: %s (%s)\n", probeData.Format.FormatName, probeData.Format.FormatLongName)
-	fmt.Printf("   Duration: %s seconds\n", probeData.Format.Duration)
-	fmt.Printf("   Size: %s bytes\n", probeData.Format.Size)
-	fmt.Printf("   Bit Rate: %s bps\n", probeData.Format.BitRate)
+	if !opts.SkipVideo && info.HasVideo() {
+		extractor := NewVideoExtractor(opts.TempDir)
+		extractor.FFmpegPath = opts.FFmpegPath
+		extractor.Workspace = opts.Workspace
 
-	// Print stream information
-	fmt.Printf("\n🎬 Streams (%d total):\n", len(probeData.Streams))
-	
-	for i, stream := range probeData.Streams {
-		fmt.Printf("\n   Stream %d (%s):\n", i, stream.CodecType)
-		fmt.Printf("      Codec: %s (%s)\n", stream.CodecName, stream.CodecLongName)
-		
-		if stream.CodecType == "video" {
-			fmt.Printf("      Resolution: %dx%d\n", stream.Width, stream.Height)
-			if stream.PixelFormat != "" {
-				fmt.Printf("      Pixel Format: %s\n", stream.PixelFormat)
-			}
-		}
-		
-		if stream.CodecType == "audio" {
-			fmt.Printf("      Sample Rate: %s Hz\n", stream.SampleRate)
-			fmt.Printf("      Channels: %d\n", stream.Channels)
-		}
-		
-		if stream.Duration != "" {
-			fmt.Printf("      Duration: %s seconds\n", stream.Duration)
-		}
-		if stream.BitRate != "" {
-			fmt.Printf("      Bit Rate: %s bps\n", stream.BitRate)
+		result.VideoPath, err = extractor.ExtractVideoPath(ctx, inputPath)
+		if err != nil {
+			result.Cleanup()
+			return nil, err
 		}
 	}
 
-	fmt.Println("\n🎉 Analysis complete!")
-	fmt.Println("💡 u2takey/ffmpeg-go wrapper is working correctly!")
-	*/
-}
\ No newline at end of file
+	return result, nil
+}
+
+// Cleanup removes any files extracted by Analyze
+func (r *AnalysisResult) Cleanup() {
+	if r.AudioPath != "" {
+		removeTempFile(r.workspace, r.AudioPath)
+		r.AudioPath = ""
+	}
+	if r.VideoPath != "" {
+		removeTempFile(r.workspace, r.VideoPath)
+		r.VideoPath = ""
+	}
+}
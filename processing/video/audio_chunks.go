@@ -0,0 +1,56 @@
+package video
+
+import (
+	"fmt"
+	"time"
+)
+
+// Default chunking used for transcription requests
+const (
+	DefaultChunkLength  = 5 * time.Minute
+	DefaultChunkOverlap = 2 * time.Second
+)
+
+// ChunkOptions controls how long audio is split for transcription
+type ChunkOptions struct {
+	Length  time.Duration
+	Overlap time.Duration
+}
+
+// ChunkSpan is a planned time range for a single chunk
+type ChunkSpan struct {
+	Start    time.Duration
+	Duration time.Duration
+}
+
+// PlanChunks splits total into spans of opts.Length where each span after the
+// first starts opts.Overlap before the previous one ends, so words spoken on
+// a boundary appear whole in at least one chunk
+func PlanChunks(total time.Duration, opts ChunkOptions) ([]ChunkSpan, error) {
+	if total <= 0 {
+		return nil, fmt.Errorf("cannot chunk media with unknown duration")
+	}
+	if opts.Length <= 0 {
+		opts.Length = DefaultChunkLength
+	}
+	if opts.Overlap < 0 || opts.Overlap >= opts.Length {
+		return nil, fmt.Errorf("chunk overlap %s must be between 0 and chunk length %s", opts.Overlap, opts.Length)
+	}
+
+	step := opts.Length - opts.Overlap
+	var spans []ChunkSpan
+	for start := time.Duration(0); start < total; start += step {
+		duration := opts.Length
+		if start+duration > total {
+			duration = total - start
+		}
+		spans = append(spans, ChunkSpan{Start: start, Duration: duration})
+
+		// The last chunk already reaches the end, don't emit a tail that is
+		// entirely overlap
+		if start+duration >= total {
+			break
+		}
+	}
+	return spans, nil
+}
@@ -0,0 +1,68 @@
+package video
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestPlanChunks(t *testing.T) {
+	const (
+		s = time.Second
+		m = time.Minute
+	)
+
+	tests := []struct {
+		name    string
+		total   time.Duration
+		opts    ChunkOptions
+		want    []ChunkSpan
+		wantErr bool
+	}{
+		{
+			name:  "shorter than a chunk",
+			total: 90 * s,
+			opts:  ChunkOptions{Length: 5 * m, Overlap: 2 * s},
+			want:  []ChunkSpan{{0, 90 * s}},
+		},
+		{
+			name:  "overlapping chunks with a short tail",
+			total: 10 * m,
+			opts:  ChunkOptions{Length: 5 * m, Overlap: 2 * s},
+			want:  []ChunkSpan{{0, 5 * m}, {4*m + 58*s, 5 * m}, {9*m + 56*s, 4 * s}},
+		},
+		{
+			name:  "exact multiple without overlap",
+			total: 10 * m,
+			opts:  ChunkOptions{Length: 5 * m},
+			want:  []ChunkSpan{{0, 5 * m}, {5 * m, 5 * m}},
+		},
+		{
+			name:  "no tail that is only overlap",
+			total: 9*m + 58*s,
+			opts:  ChunkOptions{Length: 5 * m, Overlap: 2 * s},
+			want:  []ChunkSpan{{0, 5 * m}, {4*m + 58*s, 5 * m}},
+		},
+		{
+			name:  "default length",
+			total: 6 * m,
+			opts:  ChunkOptions{},
+			want:  []ChunkSpan{{0, DefaultChunkLength}, {DefaultChunkLength, 6*m - DefaultChunkLength}},
+		},
+		{name: "unknown duration", total: 0, opts: ChunkOptions{Length: m}, wantErr: true},
+		{name: "overlap as long as a chunk", total: 10 * m, opts: ChunkOptions{Length: m, Overlap: m}, wantErr: true},
+		{name: "negative overlap", total: 10 * m, opts: ChunkOptions{Length: m, Overlap: -s}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := PlanChunks(tt.total, tt.opts)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("PlanChunks() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("PlanChunks() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,263 @@
+package video
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"os/exec"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	ffmpeg "github.com/u2takey/ffmpeg-go"
+)
+
+// Format of PCM audio produced by StreamAudio: 16kHz mono signed 16-bit little endian,
+// the same layout the WAV extraction uses
+const (
+	StreamSampleRate     = 16000
+	StreamChannels       = 1
+	StreamBytesPerSample = 2
+)
+
+// AudioStream is a live ffmpeg process decoding a video's audio track to raw PCM.
+// It implements io.ReadCloser; Close must always be called to reap ffmpeg.
+type AudioStream struct {
+	cmd    *exec.Cmd
+	stdout io.ReadCloser
+	stderr *bytes.Buffer
+	parent context.Context
+	cancel context.CancelFunc
+	eof    atomic.Bool // ffmpeg's output was read to the end
+}
+
+// AudioFrame is a fixed-length slice of decoded samples
+type AudioFrame struct {
+	Offset  time.Duration // Position of the first sample within the source
+	Samples []int16
+}
+
+// StreamAudio starts ffmpeg and returns a reader over its PCM output without
+// writing anything to disk
+func (ae *AudioExtractor) StreamAudio(parent context.Context, videoPath string) (*AudioStream, error) {
+	ctx, cancel := context.WithCancel(parent)
+
+	stderr := &bytes.Buffer{}
+	cmd := ffmpeg.OutputContext(ctx, []*ffmpeg.Stream{ffmpeg.Input(videoPath)}, "pipe:", ffmpeg.KwArgs{
+		"vn":     "",               // No video
+		"acodec": "pcm_s16le",      // 16-bit PCM codec
+		"ar":     StreamSampleRate, // 16kHz sample rate
+		"ac":     StreamChannels,   // Mono audio
+		"f":      "s16le",          // Raw samples, no container
+	}).
+		WithErrorOutput(stderr).
+		SetFfmpegPath(ae.FFmpegPath).
+		Silent(true).
+		Compile()
+
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		cancel()
+		return nil, fmt.Errorf("failed to open ffmpeg stdout: %w", err)
+	}
+
+	if err := cmd.Start(); err != nil {
+		cancel()
+		return nil, fmt.Errorf("failed to start ffmpeg: %w", err)
+	}
+
+	return &AudioStream{
+		cmd:    cmd,
+		stdout: stdout,
+		stderr: stderr,
+		parent: parent,
+		cancel: cancel,
+	}, nil
+}
+
+// Read reads raw PCM bytes from ffmpeg
+func (s *AudioStream) Read(p []byte) (int, error) {
+	n, err := s.stdout.Read(p)
+	if err == io.EOF {
+		s.eof.Store(true)
+	}
+	return n, err
+}
+
+// Close stops ffmpeg if it is still running and reports any decode failure.
+// Closing before the stream is fully read is not an error.
+func (s *AudioStream) Close() error {
+	defer s.cancel()
+
+	// Once the output has ended ffmpeg exits by itself; only a reader that
+	// stopped early needs it killed, and then the exit status means nothing
+	early := !s.eof.Load()
+	if early {
+		s.cancel()
+	}
+	err := s.cmd.Wait()
+
+	if s.parent.Err() != nil {
+		return s.parent.Err()
+	}
+	if err != nil && !early {
+		return fmt.Errorf("ffmpeg audio stream failed: %w: %s", err, strings.TrimSpace(s.stderr.String()))
+	}
+	return nil
+}
+
+// Frames decodes the stream into frames of frameDuration on a channel buffered
+// to at most buffer frames, so memory stays bounded however long the source is.
+// Both channels are closed when the stream ends; at most one error is sent.
+// ffmpeg's own exit status is reported by Close.
+func (s *AudioStream) Frames(ctx context.Context, frameDuration time.Duration, buffer int) (<-chan AudioFrame, <-chan error) {
+	frames := make(chan AudioFrame, buffer)
+	errs := make(chan error, 1)
+
+	samplesPerFrame := int(frameDuration.Seconds() * StreamSampleRate * StreamChannels)
+	if samplesPerFrame <= 0 {
+		samplesPerFrame = StreamSampleRate / 10
+	}
+
+	go func() {
+		defer close(frames)
+		defer close(errs)
+
+		raw := make([]byte, samplesPerFrame*StreamBytesPerSample)
+		var position int64
+		for {
+			n, err := io.ReadFull(s, raw)
+			if n > 0 {
+				samples := make([]int16, n/StreamBytesPerSample)
+				for i := range samples {
+					samples[i] = int16(binary.LittleEndian.Uint16(raw[i*2:]))
+				}
+
+				frame := AudioFrame{
+					Offset:  time.Duration(position) * time.Second / StreamSampleRate,
+					Samples: samples,
+				}
+				position += int64(len(samples) / StreamChannels)
+
+				select {
+				case frames <- frame:
+				case <-ctx.Done():
+					errs <- ctx.Err()
+					return
+				}
+			}
+
+			if err == io.EOF || err == io.ErrUnexpectedEOF {
+				return
+			}
+			if err != nil {
+				errs <- fmt.Errorf("failed to read audio stream: %w", err)
+				return
+			}
+		}
+	}()
+
+	return frames, errs
+}
+
+// PCMChunk is one of the overlapping chunks StreamChunks cuts the audio into
+type PCMChunk struct {
+	Index    int
+	Start    time.Duration // Offset of the chunk within the source
+	Duration time.Duration
+	Samples  []int16
+}
+
+// StreamChunks decodes the audio track in a single ffmpeg pass and calls fn
+// with each of the chunks PlanChunks plans for total as soon as the chunk has
+// been decoded. Only the chunk being filled is held in memory, so a
+// multi-hour source needs no more than a short one, and nothing is written
+// to disk. An error from fn stops decoding and is returned.
+func (ae *AudioExtractor) StreamChunks(ctx context.Context, videoPath string, total time.Duration, opts ChunkOptions, fn func(PCMChunk) error) error {
+	spans, err := PlanChunks(total, opts)
+	if err != nil {
+		return err
+	}
+
+	stream, err := ae.StreamAudio(ctx, videoPath)
+	if err != nil {
+		return err
+	}
+
+	err = cutChunks(ctx, stream, spans, fn)
+	if closeErr := stream.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+// cutChunks reads the stream's frames into the planned spans. Spans past the
+// end of the decoded audio, when the probed duration was too long, are cut
+// short or skipped.
+func cutChunks(parent context.Context, stream *AudioStream, spans []ChunkSpan, fn func(PCMChunk) error) error {
+	// Stops the frame reader if fn fails before the stream ends
+	ctx, cancel := context.WithCancel(parent)
+	defer cancel()
+	frames, errs := stream.Frames(ctx, time.Second, 4)
+
+	sampleAt := func(d time.Duration) int64 {
+		return int64(d.Seconds() * StreamSampleRate)
+	}
+
+	var buffer []int16 // Decoded samples from bufferStart on
+	var bufferStart int64
+	next := 0
+	emit := func(end int64) error {
+		span := spans[next]
+		from := max(sampleAt(span.Start)-bufferStart, 0)
+		to := min(end-bufferStart, int64(len(buffer)))
+		if from < to {
+			chunk := PCMChunk{
+				Index:    next,
+				Start:    span.Start,
+				Duration: time.Duration(to-from) * time.Second / StreamSampleRate,
+				Samples:  buffer[from:to],
+			}
+			if err := fn(chunk); err != nil {
+				return err
+			}
+		}
+		next++
+
+		// Drop everything before the next chunk; the overlap is kept
+		if next < len(spans) {
+			drop := min(max(sampleAt(spans[next].Start)-bufferStart, 0), int64(len(buffer)))
+			buffer = append([]int16(nil), buffer[drop:]...)
+			bufferStart += drop
+		}
+		return nil
+	}
+
+	for frame := range frames {
+		buffer = append(buffer, frame.Samples...)
+		for next < len(spans) {
+			end := sampleAt(spans[next].Start + spans[next].Duration)
+			if bufferStart+int64(len(buffer)) < end {
+				break
+			}
+			if err := emit(end); err != nil {
+				return err
+			}
+		}
+	}
+	if err := <-errs; err != nil {
+		return err
+	}
+	if err := parent.Err(); err != nil {
+		return err
+	}
+
+	for next < len(spans) {
+		if err := emit(bufferStart + int64(len(buffer))); err != nil {
+			return err
+		}
+	}
+	return nil
+}
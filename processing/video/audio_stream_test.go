@@ -0,0 +1,68 @@
+package video
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestAudioStreamClose(t *testing.T) {
+	tests := []struct {
+		name    string
+		script  string // Stand-in for ffmpeg, which ignores its arguments
+		readAll bool
+		cancel  bool
+		wantErr string
+	}{
+		{name: "clean exit", script: "printf pcm", readAll: true},
+		{name: "failed exit", script: "printf pcm; echo 'Invalid data found' >&2; exit 1", readAll: true, wantErr: "Invalid data found"},
+		{name: "killed by a signal", script: "printf pcm; kill -9 $$", readAll: true, wantErr: "signal: killed"},
+		{name: "closed before the end", script: "while :; do printf pcm; done"},
+		{name: "cancelled", script: "while :; do printf pcm; done", cancel: true, wantErr: context.Canceled.Error()},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ffmpeg := filepath.Join(t.TempDir(), "ffmpeg")
+			if err := os.WriteFile(ffmpeg, []byte("#!/bin/sh\n"+tt.script+"\n"), 0o755); err != nil {
+				t.Fatal(err)
+			}
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			extractor := NewAudioExtractor(t.TempDir())
+			extractor.FFmpegPath = ffmpeg
+			stream, err := extractor.StreamAudio(ctx, "input.mp4")
+			if err != nil {
+				t.Fatalf("StreamAudio() error = %v", err)
+			}
+
+			if tt.readAll {
+				_, err = io.Copy(io.Discard, stream)
+			} else {
+				_, err = stream.Read(make([]byte, 3))
+			}
+			if err != nil {
+				t.Fatalf("reading stream: %v", err)
+			}
+			if tt.cancel {
+				cancel()
+			}
+
+			err = stream.Close()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Close() error = %v, want nil", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Close() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
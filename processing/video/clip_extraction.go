@@ -0,0 +1,246 @@
+package video
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	ffmpeg "github.com/u2takey/ffmpeg-go"
+)
+
+// ClipMode selects how ExtractClip cuts a segment
+type ClipMode string
+
+const (
+	// ClipModeAuto stream-copies when the start is on a keyframe and re-encodes otherwise
+	ClipModeAuto ClipMode = "auto"
+	// ClipModeCopy stream-copies from the keyframe at or before the start. Fast and
+	// lossless, but the clip may begin slightly early.
+	ClipModeCopy ClipMode = "copy"
+	// ClipModeReencode re-encodes the segment so it starts exactly on the requested frame
+	ClipModeReencode ClipMode = "reencode"
+)
+
+// DefaultKeyframeTolerance is how far the start may be from a keyframe for
+// ClipModeAuto to still stream-copy; about one frame at 24fps
+const DefaultKeyframeTolerance = 42 * time.Millisecond
+
+// EncodeSettings configures the encoder used for re-encoded clips. The
+// mapstructure tags are the field names used in the quality-presets config.
+type EncodeSettings struct {
+	VideoCodec   string `mapstructure:"codec" json:"codec"`
+	CRF          int    `mapstructure:"crf" json:"crf"`
+	Preset       string `mapstructure:"preset" json:"preset"`
+	MaxBitrate   string `mapstructure:"max-bitrate" json:"max_bitrate"` // e.g. "4M"; empty for no cap
+	AudioCodec   string `mapstructure:"audio-codec" json:"audio_codec"`
+	AudioBitrate string `mapstructure:"audio-bitrate" json:"audio_bitrate"` // e.g. "128k"; empty for the encoder default
+	MaxHeight    int    `mapstructure:"max-height" json:"max_height"`       // Taller frames are scaled down, or wider ones for portrait output; 0 for no cap
+}
+
+// ClipOptions configures ExtractClip
+type ClipOptions struct {
+	Mode              ClipMode
+	Encode            EncodeSettings
+	KeyframeTolerance time.Duration
+	// OutputPath is where the clip is written; when empty a temp file is allocated
+	OutputPath string
+	// Subtitles optionally names an ASS script, timed from the clip's start,
+	// to burn into the picture. Burning in always re-encodes.
+	Subtitles string
+	// Reframe optionally changes the aspect ratio, which always re-encodes.
+	// FrameWidth and FrameHeight are the source's display size and are
+	// required when reframing.
+	Reframe     ReframeOptions
+	FrameWidth  int
+	FrameHeight int
+	// Progress optionally receives how much of the clip has been written
+	Progress ProgressFunc
+}
+
+// DefaultClipOptions returns automatic mode with the built-in medium quality encode
+func DefaultClipOptions() ClipOptions {
+	return ClipOptions{
+		Mode:              ClipModeAuto,
+		Encode:            QualityPresets[DefaultQuality],
+		KeyframeTolerance: DefaultKeyframeTolerance,
+	}
+}
+
+// ClipResult describes a clip written by ExtractClip
+type ClipResult struct {
+	Path string
+	Mode ClipMode // The mode actually used, never ClipModeAuto
+	// Start and End are the clip's actual bounds in the source, which differ
+	// from the requested ones only for stream-copied clips
+	Start time.Duration
+	End   time.Duration
+}
+
+// ExtractClip cuts the segment between start and end, with audio, into an MP4.
+// In ClipModeAuto the nearest keyframe decides between a stream copy and a re-encode.
+func (ve *VideoExtractor) ExtractClip(ctx context.Context, inputPath string, start, end time.Duration, opts ClipOptions) (*ClipResult, error) {
+	if start < 0 || end <= start {
+		return nil, fmt.Errorf("invalid clip range %s - %s", start, end)
+	}
+	if opts.Mode == "" {
+		opts.Mode = ClipModeAuto
+	}
+
+	result := &ClipResult{Mode: opts.Mode, Start: start, End: end}
+
+	if opts.Reframe.Enabled() && (opts.FrameWidth <= 0 || opts.FrameHeight <= 0) {
+		return nil, fmt.Errorf("cannot reframe a clip without knowing the source frame size")
+	}
+	if opts.Subtitles != "" || opts.Reframe.Enabled() {
+		if opts.Mode == ClipModeCopy {
+			return nil, fmt.Errorf("cannot burn subtitles into or reframe a stream-copied clip")
+		}
+		opts.Mode = ClipModeReencode
+		result.Mode = ClipModeReencode
+	}
+
+	switch opts.Mode {
+	case ClipModeAuto, ClipModeCopy:
+		keyframe, ok, err := KeyframeBefore(ctx, ve.FFprobePath, inputPath, start)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+			if opts.Mode == ClipModeCopy {
+				return nil, fmt.Errorf("failed to find keyframe for stream copy: %w", err)
+			}
+			// A re-encode is always correct, just slower
+			ok = false
+		}
+
+		switch {
+		case ok && (opts.Mode == ClipModeCopy || start-keyframe <= opts.KeyframeTolerance):
+			result.Mode = ClipModeCopy
+			result.Start = keyframe
+		case opts.Mode == ClipModeCopy:
+			// No keyframe within the lookback window; the demuxer will pick one
+			result.Mode = ClipModeCopy
+		default:
+			result.Mode = ClipModeReencode
+		}
+	case ClipModeReencode:
+	default:
+		return nil, fmt.Errorf("unknown clip mode %q (use auto, copy or reencode)", opts.Mode)
+	}
+
+	result.Path = opts.OutputPath
+	if result.Path == "" {
+		path, err := newTempFile(ve.Workspace, ve.TempDir, "clip", ".mp4")
+		if err != nil {
+			return nil, err
+		}
+		result.Path = path
+	}
+
+	// Seeking on the input is fast, and frame-accurate when re-encoding since
+	// ffmpeg decodes from the previous keyframe and discards frames before start
+	input := ffmpeg.Input(inputPath, ffmpeg.KwArgs{"ss": result.Start.Seconds()})
+
+	outputArgs := ffmpeg.KwArgs{
+		"t":        (result.End - result.Start).Seconds(),
+		"movflags": "+faststart", // Playable before fully downloaded
+	}
+	if result.Mode == ClipModeCopy {
+		outputArgs["c"] = "copy"
+		outputArgs["avoid_negative_ts"] = "make_zero"
+	} else {
+		for k, v := range opts.Encode.outputArgs() {
+			outputArgs[k] = v
+		}
+
+		// Reframe first so captions are laid out on the final frame, then
+		// render captions before scaling so they scale with the picture
+		var filters []string
+		portrait := false
+		if opts.Reframe.Enabled() {
+			var commands string
+			if opts.Reframe.Mode == ReframeTrack {
+				var err error
+				commands, err = ve.writeTrackCommands(ctx, inputPath, result.Start, result.End, opts.Reframe, opts.FrameWidth, opts.FrameHeight)
+				if err != nil {
+					return nil, err
+				}
+			}
+			filters = append(filters, opts.Reframe.filter(opts.FrameWidth, opts.FrameHeight, commands))
+			w, h := opts.Reframe.OutputSize(opts.FrameWidth, opts.FrameHeight)
+			portrait = h > w
+		}
+		if opts.Subtitles != "" {
+			filters = append(filters, "ass="+escapeFilterArg(opts.Subtitles))
+		}
+		if scale := opts.Encode.scaleFilter(portrait); scale != "" {
+			filters = append(filters, scale)
+		}
+		if len(filters) > 0 {
+			outputArgs["vf"] = strings.Join(filters, ",")
+		}
+	}
+
+	stream := ffmpeg.OutputContext(ctx, []*ffmpeg.Stream{input}, result.Path, withProgress(outputArgs, opts.Progress))
+	err := withProgressOutput(stream, opts.Progress).
+		OverWriteOutput().
+		SetFfmpegPath(ve.FFmpegPath).
+		Silent(true).
+		Run()
+	if err != nil {
+		os.Remove(result.Path)
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		return nil, fmt.Errorf("failed to extract clip (%s): %w", result.Mode, err)
+	}
+
+	return result, nil
+}
+
+// outputArgs converts the settings into ffmpeg output options
+func (s EncodeSettings) outputArgs() ffmpeg.KwArgs {
+	args := ffmpeg.KwArgs{
+		"c:v":     s.VideoCodec,
+		"crf":     s.CRF,
+		"c:a":     s.AudioCodec,
+		"pix_fmt": "yuv420p", // Widest player compatibility
+	}
+	if s.Preset != "" {
+		args["preset"] = s.Preset
+	}
+	if s.MaxBitrate != "" {
+		// Capped CRF: quality-driven, but never above maxrate over a 2s window
+		args["maxrate"] = s.MaxBitrate
+		if bps, err := ParseBitrate(s.MaxBitrate); err == nil {
+			args["bufsize"] = 2 * bps
+		}
+	}
+	if s.AudioBitrate != "" {
+		args["b:a"] = s.AudioBitrate
+	}
+	return args
+}
+
+// scaleFilter caps the frame at MaxHeight, or "" for no cap. Portrait frames
+// are capped by width instead, so a 720p preset gives 720x1280 vertical clips.
+func (s EncodeSettings) scaleFilter(portrait bool) string {
+	if s.MaxHeight <= 0 {
+		return ""
+	}
+	// Only ever scale down; -2 keeps the other side even as x264 requires
+	if portrait {
+		return fmt.Sprintf("scale=min(%d\\,iw):-2", s.MaxHeight)
+	}
+	return fmt.Sprintf("scale=-2:min(%d\\,ih)", s.MaxHeight)
+}
+
+// escapeFilterArg escapes a value, such as a file path, for use as a filter
+// option inside a filtergraph. ffmpeg unescapes the graph and then the option
+// value, so both levels are applied.
+func escapeFilterArg(value string) string {
+	option := strings.NewReplacer(`\`, `\\`, `'`, `\'`, `:`, `\:`).Replace(value)
+	return strings.NewReplacer(`\`, `\\`, `'`, `\'`, `,`, `\,`, `;`, `\;`, `[`, `\[`, `]`, `\]`).Replace(option)
+}
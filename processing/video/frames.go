@@ -0,0 +1,439 @@
+package video
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	ffmpeg "github.com/u2takey/ffmpeg-go"
+)
+
+// PixelFormat is the layout of raw frames from SampleFrames
+type PixelFormat string
+
+const (
+	PixelRGB24 PixelFormat = "rgb24" // 3 bytes per pixel, R G B
+	PixelGray  PixelFormat = "gray"  // 1 byte of luma per pixel
+)
+
+// DefaultFrameInterval matches the 1-2 second sampling visual analysis needs
+const DefaultFrameInterval = time.Second
+
+// DefaultSceneThreshold is the scene change score, from 0 to 1, above which
+// a frame starts a new scene. It catches hard cuts without firing on
+// ordinary camera movement.
+const DefaultSceneThreshold = 0.3
+
+// frameStderrLines is how much of ffmpeg's log is kept for error messages
+const frameStderrLines = 20
+
+// FrameSampleOptions selects which frames SampleFrames and ExtractFrameImages
+// decode and how they are scaled
+type FrameSampleOptions struct {
+	// Interval takes one frame per interval, DefaultFrameInterval when zero
+	Interval time.Duration
+	// SceneThreshold, when above zero, takes a frame at each scene change
+	// scoring above it instead, see DefaultSceneThreshold
+	SceneThreshold float64
+
+	Start time.Duration
+	End   time.Duration // Zero samples to the end of the source
+
+	// Width and Height scale frames down; when one is zero the other keeps
+	// the aspect ratio, and when both are zero frames keep the source size
+	Width  int
+	Height int
+
+	// PixelFormat of raw frames, PixelRGB24 when empty. Ignored for images.
+	PixelFormat PixelFormat
+}
+
+// Frame is one decoded frame
+type Frame struct {
+	Index     int
+	Timestamp time.Duration // Position in the source
+	Width     int
+	Height    int
+	Format    PixelFormat
+	Pix       []byte // Raw pixels row by row, from SampleFrames
+	Path      string // Image file, from ExtractFrameImages
+}
+
+// FrameDescriptor summarises a frame for visual analysis without keeping its
+// pixels
+type FrameDescriptor struct {
+	At         time.Duration `json:"at"`
+	Brightness float64       `json:"brightness"` // Mean luma, 0 to 1
+	Contrast   float64       `json:"contrast"`   // Standard deviation of luma, 0 to 1
+	Saturation float64       `json:"saturation"` // Mean spread between colour channels, 0 to 1
+	Color      string        `json:"color"`      // Mean colour as #RRGGBB
+}
+
+// Describe summarises a raw frame from SampleFrames
+func (f Frame) Describe() FrameDescriptor {
+	channels := 3
+	if f.Format == PixelGray {
+		channels = 1
+	}
+	pixels := len(f.Pix) / channels
+	d := FrameDescriptor{At: f.Timestamp, Color: "#000000"}
+	if pixels == 0 {
+		return d
+	}
+
+	var sumR, sumG, sumB, sumY, sumYY, sumSat float64
+	for i := 0; i < pixels; i++ {
+		p := f.Pix[i*channels : (i+1)*channels]
+		r, g, b := float64(p[0]), float64(p[0]), float64(p[0])
+		if channels == 3 {
+			g, b = float64(p[1]), float64(p[2])
+		}
+		y := 0.299*r + 0.587*g + 0.114*b
+		sumR += r
+		sumG += g
+		sumB += b
+		sumY += y
+		sumYY += y * y
+		sumSat += max(r, g, b) - min(r, g, b)
+	}
+
+	n := float64(pixels)
+	mean := sumY / n
+	d.Brightness = mean / 255
+	d.Contrast = math.Sqrt(max(sumYY/n-mean*mean, 0)) / 255
+	d.Saturation = sumSat / n / 255
+	d.Color = fmt.Sprintf("#%02X%02X%02X", int(sumR/n+0.5), int(sumG/n+0.5), int(sumB/n+0.5))
+	return d
+}
+
+// frameInfo is what showinfo logs about each frame leaving the filtergraph
+type frameInfo struct {
+	pts    time.Duration
+	width  int
+	height int
+}
+
+var (
+	showinfoTime = regexp.MustCompile(`pts_time:\s*(-?[0-9.]+)`)
+	showinfoSize = regexp.MustCompile(`\bs:(\d+)x(\d+)`)
+)
+
+// SampleFrames decodes frames into raw pixels over a pipe, so nothing is
+// written to disk. Frames arrive on a channel buffered to at most buffer
+// frames; both channels are closed when sampling ends and at most one error
+// is sent. Stop early by cancelling ctx.
+func (ve *VideoExtractor) SampleFrames(ctx context.Context, inputPath string, opts FrameSampleOptions, buffer int) (<-chan Frame, <-chan error) {
+	frames := make(chan Frame, buffer)
+	errs := make(chan error, 1)
+
+	format := opts.PixelFormat
+	if format == "" {
+		format = PixelRGB24
+	}
+	bytesPerPixel := 3
+	if format == PixelGray {
+		bytesPerPixel = 1
+	}
+
+	go func() {
+		defer close(frames)
+		defer close(errs)
+
+		stdoutR, stdoutW := io.Pipe()
+		log := newFrameLog()
+		args := opts.outputArgs()
+		args["f"] = "rawvideo"
+		args["pix_fmt"] = string(format)
+
+		cmd := ffmpeg.OutputContext(ctx, []*ffmpeg.Stream{opts.input(inputPath)}, "pipe:", args).
+			WithOutput(stdoutW).
+			WithErrorOutput(log).
+			SetFfmpegPath(ve.FFmpegPath).
+			Silent(true).
+			Compile()
+		if err := cmd.Start(); err != nil {
+			errs <- fmt.Errorf("failed to start ffmpeg: %w", err)
+			return
+		}
+
+		waitErr := make(chan error, 1)
+		go func() {
+			err := cmd.Wait()
+			stdoutW.CloseWithError(io.EOF)
+			log.close()
+			waitErr <- err
+		}()
+
+		// showinfo logs each frame before ffmpeg writes its pixels, so the
+		// frame's size and timestamp are known before reading it
+		reader := bufio.NewReader(stdoutR)
+		index := 0
+		for info := range log.frames {
+			pix := make([]byte, info.width*info.height*bytesPerPixel)
+			if _, err := io.ReadFull(reader, pix); err != nil {
+				break
+			}
+
+			frame := Frame{
+				Index:     index,
+				Timestamp: opts.Start + info.pts,
+				Width:     info.width,
+				Height:    info.height,
+				Format:    format,
+				Pix:       pix,
+			}
+			index++
+
+			select {
+			case frames <- frame:
+			case <-ctx.Done():
+				stdoutR.Close()
+				go log.drain()
+				<-waitErr
+				errs <- ctx.Err()
+				return
+			}
+		}
+
+		// Unblock ffmpeg if it wrote more than showinfo reported
+		go io.Copy(io.Discard, stdoutR)
+		go log.drain()
+		if err := <-waitErr; err != nil {
+			if ctx.Err() != nil {
+				errs <- ctx.Err()
+				return
+			}
+			errs <- fmt.Errorf("failed to sample frames: %w: %s", err, log.tail())
+		}
+	}()
+
+	return frames, errs
+}
+
+// ExtractFrameImages decodes frames into image files, JPEG unless ext is
+// ".png", named frame_000001 onwards in dir. When dir is empty a new
+// directory is created in the workspace. Callers own the directory.
+func (ve *VideoExtractor) ExtractFrameImages(ctx context.Context, inputPath string, opts FrameSampleOptions, dir, ext string) ([]Frame, error) {
+	if ext == "" {
+		ext = ".jpg"
+	}
+
+	created := dir == ""
+	if created {
+		var err error
+		if dir, err = ve.newFrameDir(); err != nil {
+			return nil, err
+		}
+	}
+
+	log := newFrameLog()
+	var infos []frameInfo
+	collected := make(chan struct{})
+	go func() {
+		for info := range log.frames {
+			infos = append(infos, info)
+		}
+		close(collected)
+	}()
+
+	args := opts.outputArgs()
+	if ext != ".png" {
+		args["q:v"] = 3 // High quality JPEG; 2-31, lower is better
+	}
+	err := ffmpeg.OutputContext(ctx, []*ffmpeg.Stream{opts.input(inputPath)}, filepath.Join(dir, "frame_%06d"+ext), args).
+		WithErrorOutput(log).
+		OverWriteOutput().
+		SetFfmpegPath(ve.FFmpegPath).
+		Silent(true).
+		Run()
+	log.close()
+	<-collected
+	if err != nil {
+		if created {
+			os.RemoveAll(dir)
+		}
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		return nil, fmt.Errorf("failed to extract frames: %w: %s", err, log.tail())
+	}
+
+	paths, err := filepath.Glob(filepath.Join(dir, "frame_*"+ext))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list extracted frames: %w", err)
+	}
+	sort.Strings(paths)
+
+	frames := make([]Frame, 0, len(paths))
+	for i, path := range paths {
+		frame := Frame{Index: i, Path: path}
+		if i < len(infos) {
+			frame.Timestamp = opts.Start + infos[i].pts
+			frame.Width, frame.Height = infos[i].width, infos[i].height
+		}
+		frames = append(frames, frame)
+	}
+	return frames, nil
+}
+
+// newFrameDir creates a directory for extracted frame images
+func (ve *VideoExtractor) newFrameDir() (string, error) {
+	if ve.Workspace != nil {
+		return ve.Workspace.NewDir("frames")
+	}
+	if err := os.MkdirAll(ve.TempDir, 0755); err != nil {
+		return "", fmt.Errorf("failed to create temp directory: %w", err)
+	}
+	dir, err := os.MkdirTemp(ve.TempDir, "frames_*")
+	if err != nil {
+		return "", fmt.Errorf("failed to create frame directory: %w", err)
+	}
+	return dir, nil
+}
+
+// input seeks to the start of the sampled range
+func (o FrameSampleOptions) input(inputPath string) *ffmpeg.Stream {
+	if o.Start > 0 {
+		return ffmpeg.Input(inputPath, ffmpeg.KwArgs{"ss": o.Start.Seconds()})
+	}
+	return ffmpeg.Input(inputPath)
+}
+
+// outputArgs selects, scales and logs the sampled frames
+func (o FrameSampleOptions) outputArgs() ffmpeg.KwArgs {
+	var scale string
+	if o.Width > 0 || o.Height > 0 {
+		scale = fmt.Sprintf("scale=%d:%d", scaleSide(o.Width), scaleSide(o.Height))
+	}
+
+	// Scene scores are computed on every decoded frame, so scale first to
+	// keep that cheap; interval sampling drops frames before scaling instead
+	var filters []string
+	if o.SceneThreshold > 0 {
+		filters = appendFilter(filters, scale)
+		filters = append(filters, fmt.Sprintf("select=gt(scene\\,%.3f)", o.SceneThreshold))
+	} else {
+		interval := o.Interval
+		if interval <= 0 {
+			interval = DefaultFrameInterval
+		}
+		filters = append(filters, fmt.Sprintf("fps=1000/%d", max(interval.Milliseconds(), 1)))
+		filters = appendFilter(filters, scale)
+	}
+	filters = append(filters, "showinfo")
+
+	args := ffmpeg.KwArgs{
+		"an":    "",
+		"vf":    strings.Join(filters, ","),
+		"vsync": "vfr", // One output frame per selected frame, no duplicates
+	}
+	if o.End > o.Start {
+		args["t"] = (o.End - o.Start).Seconds()
+	}
+	return args
+}
+
+// appendFilter appends filter unless it is empty
+func appendFilter(filters []string, filter string) []string {
+	if filter == "" {
+		return filters
+	}
+	return append(filters, filter)
+}
+
+// scaleSide returns a scale filter dimension, -2 keeping the aspect ratio
+// with an even size
+func scaleSide(n int) int {
+	if n <= 0 {
+		return -2
+	}
+	return n
+}
+
+// frameLog parses showinfo's per-frame lines out of ffmpeg's log while
+// keeping the last few lines for error messages
+type frameLog struct {
+	w      *io.PipeWriter
+	frames chan frameInfo
+	lines  []string
+	done   chan struct{}
+}
+
+func newFrameLog() *frameLog {
+	r, w := io.Pipe()
+	l := &frameLog{w: w, frames: make(chan frameInfo, 64), done: make(chan struct{})}
+
+	go func() {
+		defer close(l.done)
+		defer close(l.frames)
+
+		scanner := bufio.NewScanner(r)
+		for scanner.Scan() {
+			line := scanner.Text()
+			if info, ok := parseShowinfo(line); ok {
+				l.frames <- info
+				continue
+			}
+			l.lines = append(l.lines, line)
+			if len(l.lines) > frameStderrLines {
+				l.lines = l.lines[1:]
+			}
+		}
+		io.Copy(io.Discard, r)
+	}()
+	return l
+}
+
+func (l *frameLog) Write(p []byte) (int, error) {
+	return l.w.Write(p)
+}
+
+// close ends parsing once ffmpeg has exited
+func (l *frameLog) close() {
+	l.w.Close()
+}
+
+// drain discards frame infos nobody will read so ffmpeg can finish logging
+func (l *frameLog) drain() {
+	for range l.frames {
+	}
+}
+
+// tail returns the end of ffmpeg's log once parsing has finished
+func (l *frameLog) tail() string {
+	<-l.done
+	return strings.TrimSpace(strings.Join(l.lines, "\n"))
+}
+
+// parseShowinfo reads a frame's timestamp and size from a showinfo log line
+func parseShowinfo(line string) (frameInfo, bool) {
+	if !strings.Contains(line, "showinfo") {
+		return frameInfo{}, false
+	}
+	t := showinfoTime.FindStringSubmatch(line)
+	s := showinfoSize.FindStringSubmatch(line)
+	if t == nil || s == nil {
+		return frameInfo{}, false
+	}
+
+	seconds, err := strconv.ParseFloat(t[1], 64)
+	if err != nil {
+		return frameInfo{}, false
+	}
+	width, _ := strconv.Atoi(s[1])
+	height, _ := strconv.Atoi(s[2])
+	return frameInfo{
+		pts:    time.Duration(seconds * float64(time.Second)),
+		width:  width,
+		height: height,
+	}, true
+}
@@ -0,0 +1,47 @@
+package video
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseShowinfo(t *testing.T) {
+	tests := []struct {
+		name   string
+		line   string
+		want   frameInfo
+		wantOK bool
+	}{
+		{
+			name:   "frame line",
+			line:   "[Parsed_showinfo_2 @ 0x55d4] n:   3 pts:  12288 pts_time:0.48    duration:512 pos:  40536 fmt:yuv420p sar:1/1 s:1920x1080 i:P iskey:0 type:B",
+			want:   frameInfo{pts: 480 * time.Millisecond, width: 1920, height: 1080},
+			wantOK: true,
+		},
+		{
+			name:   "scaled sample",
+			line:   "[Parsed_showinfo_1 @ 0x1] n:0 pts:0 pts_time:12.5 s:160x90 i:P iskey:1",
+			want:   frameInfo{pts: 12500 * time.Millisecond, width: 160, height: 90},
+			wantOK: true,
+		},
+		{
+			name:   "negative timestamp",
+			line:   "[Parsed_showinfo_1 @ 0x1] n:0 pts:-1 pts_time:-0.04 s:320x180",
+			want:   frameInfo{pts: -40 * time.Millisecond, width: 320, height: 180},
+			wantOK: true,
+		},
+		{name: "showinfo config line", line: "[Parsed_showinfo_1 @ 0x1] config in time_base: 1/25, frame_rate: 25/1"},
+		{name: "progress line", line: "frame=   10 fps=0.0 q=-0.0 size=N/A time=00:00:00.40 bitrate=N/A speed=0.8x"},
+		{name: "other filter", line: "[Parsed_scale_0 @ 0x1] w:160 h:90 pts_time:1 s:160x90"},
+		{name: "empty", line: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := parseShowinfo(tt.line)
+			if ok != tt.wantOK || got != tt.want {
+				t.Errorf("parseShowinfo(%q) = %+v, %v, want %+v, %v", tt.line, got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
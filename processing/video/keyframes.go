@@ -0,0 +1,79 @@
+package video
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"os/exec"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// keyframeLookback is how far before a position Keyframes searches, long
+// enough to cover the GOP length of typical camera and screen recordings
+const keyframeLookback = 15 * time.Second
+
+// Keyframes lists the timestamps of video keyframes between from and to,
+// decoding only keyframes so it stays fast on long sources
+func Keyframes(ctx context.Context, ffprobePath, inputPath string, from, to time.Duration) ([]time.Duration, error) {
+	if from < 0 {
+		from = 0
+	}
+
+	cmd := exec.CommandContext(ctx, ffprobePath,
+		"-v", "error",
+		"-select_streams", "v:0",
+		"-skip_frame", "nokey",
+		"-show_entries", "frame=pts_time,best_effort_timestamp_time",
+		"-read_intervals", fmt.Sprintf("%.3f%%%.3f", from.Seconds(), to.Seconds()),
+		"-of", "csv=p=0",
+		inputPath,
+	)
+
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		return nil, fmt.Errorf("ffprobe keyframe scan failed: %w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+
+	var keyframes []time.Duration
+	scanner := bufio.NewScanner(&stdout)
+	for scanner.Scan() {
+		// Older ffprobe builds leave pts_time empty, so take the first usable field
+		for _, field := range strings.Split(scanner.Text(), ",") {
+			seconds, err := strconv.ParseFloat(strings.TrimSpace(field), 64)
+			if err == nil {
+				keyframes = append(keyframes, time.Duration(seconds*float64(time.Second)))
+				break
+			}
+		}
+	}
+
+	sort.Slice(keyframes, func(i, j int) bool { return keyframes[i] < keyframes[j] })
+	return keyframes, nil
+}
+
+// KeyframeBefore returns the last keyframe at or before position. ok is false
+// if none was found within keyframeLookback.
+func KeyframeBefore(ctx context.Context, ffprobePath, inputPath string, position time.Duration) (keyframe time.Duration, ok bool, err error) {
+	// Allow a millisecond of slack for timestamps rounded by ffprobe
+	keyframes, err := Keyframes(ctx, ffprobePath, inputPath, position-keyframeLookback, position+time.Millisecond)
+	if err != nil {
+		return 0, false, err
+	}
+
+	for i := len(keyframes) - 1; i >= 0; i-- {
+		if keyframes[i] <= position+time.Millisecond {
+			return keyframes[i], true, nil
+		}
+	}
+	return 0, false, nil
+}
@@ -0,0 +1,220 @@
+package video
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// MediaInfo is the typed result of probing a media file with ffprobe
+type MediaInfo struct {
+	Filename       string
+	FormatName     string
+	FormatLongName string
+	Duration       time.Duration
+	Size           int64
+	BitRate        int64
+	Tags           map[string]string
+	Streams        []StreamInfo
+}
+
+// StreamInfo describes a single audio, video or subtitle stream
+type StreamInfo struct {
+	Index         int
+	CodecType     string
+	CodecName     string
+	CodecLongName string
+	Profile       string
+	Duration      time.Duration
+	BitRate       int64
+
+	// Video fields
+	Width       int
+	Height      int
+	PixelFormat string
+	FrameRate   float64
+	Rotation    int
+
+	// Audio fields
+	SampleRate    int
+	Channels      int
+	ChannelLayout string
+
+	Language string
+	Tags     map[string]string
+}
+
+// probeData mirrors the JSON emitted by ffprobe -show_format -show_streams
+type probeData struct {
+	Format struct {
+		Filename       string            `json:"filename"`
+		FormatName     string            `json:"format_name"`
+		FormatLongName string            `json:"format_long_name"`
+		Duration       string            `json:"duration"`
+		Size           string            `json:"size"`
+		BitRate        string            `json:"bit_rate"`
+		Tags           map[string]string `json:"tags"`
+	} `json:"format"`
+	Streams []struct {
+		Index         int               `json:"index"`
+		CodecName     string            `json:"codec_name"`
+		CodecLongName string            `json:"codec_long_name"`
+		CodecType     string            `json:"codec_type"`
+		Profile       string            `json:"profile"`
+		Width         int               `json:"width"`
+		Height        int               `json:"height"`
+		PixelFormat   string            `json:"pix_fmt"`
+		AvgFrameRate  string            `json:"avg_frame_rate"`
+		RFrameRate    string            `json:"r_frame_rate"`
+		Duration      string            `json:"duration"`
+		BitRate       string            `json:"bit_rate"`
+		SampleRate    string            `json:"sample_rate"`
+		Channels      int               `json:"channels"`
+		ChannelLayout string            `json:"channel_layout"`
+		Tags          map[string]string `json:"tags"`
+		SideDataList  []struct {
+			Rotation int `json:"rotation"`
+		} `json:"side_data_list"`
+	} `json:"streams"`
+}
+
+// ParseProbe converts raw ffprobe JSON output into a MediaInfo
+func ParseProbe(data string) (MediaInfo, error) {
+	var raw probeData
+	if err := json.Unmarshal([]byte(data), &raw); err != nil {
+		return MediaInfo{}, fmt.Errorf("failed to parse probe data: %w", err)
+	}
+
+	info := MediaInfo{
+		Filename:       raw.Format.Filename,
+		FormatName:     raw.Format.FormatName,
+		FormatLongName: raw.Format.FormatLongName,
+		Duration:       parseSeconds(raw.Format.Duration),
+		Size:           parseInt(raw.Format.Size),
+		BitRate:        parseInt(raw.Format.BitRate),
+		Tags:           raw.Format.Tags,
+	}
+
+	for _, s := range raw.Streams {
+		stream := StreamInfo{
+			Index:         s.Index,
+			CodecType:     s.CodecType,
+			CodecName:     s.CodecName,
+			CodecLongName: s.CodecLongName,
+			Profile:       s.Profile,
+			Duration:      parseSeconds(s.Duration),
+			BitRate:       parseInt(s.BitRate),
+			Width:         s.Width,
+			Height:        s.Height,
+			PixelFormat:   s.PixelFormat,
+			SampleRate:    int(parseInt(s.SampleRate)),
+			Channels:      s.Channels,
+			ChannelLayout: s.ChannelLayout,
+			Language:      s.Tags["language"],
+			Tags:          s.Tags,
+		}
+
+		// Prefer the average frame rate; fall back to the base rate for streams
+		// that don't report one (e.g. some MKV remuxes)
+		stream.FrameRate = parseRational(s.AvgFrameRate)
+		if stream.FrameRate == 0 {
+			stream.FrameRate = parseRational(s.RFrameRate)
+		}
+
+		// Rotation lives in the display matrix side data on newer ffprobe
+		// builds and in the "rotate" tag on older ones
+		for _, side := range s.SideDataList {
+			if side.Rotation != 0 {
+				stream.Rotation = side.Rotation
+			}
+		}
+		if stream.Rotation == 0 {
+			stream.Rotation = int(parseInt(s.Tags["rotate"]))
+		}
+
+		info.Streams = append(info.Streams, stream)
+	}
+
+	// Some containers only report duration per stream
+	if info.Duration == 0 {
+		for _, s := range info.Streams {
+			if s.Duration > info.Duration {
+				info.Duration = s.Duration
+			}
+		}
+	}
+
+	return info, nil
+}
+
+// VideoStream returns the first video stream, if any
+func (m MediaInfo) VideoStream() (StreamInfo, bool) {
+	return m.firstStream("video")
+}
+
+// AudioStream returns the first audio stream, if any
+func (m MediaInfo) AudioStream() (StreamInfo, bool) {
+	return m.firstStream("audio")
+}
+
+// HasAudio reports whether the file contains at least one audio stream
+func (m MediaInfo) HasAudio() bool {
+	_, ok := m.AudioStream()
+	return ok
+}
+
+// HasVideo reports whether the file contains at least one video stream
+func (m MediaInfo) HasVideo() bool {
+	_, ok := m.VideoStream()
+	return ok
+}
+
+func (m MediaInfo) firstStream(codecType string) (StreamInfo, bool) {
+	for _, s := range m.Streams {
+		if s.CodecType == codecType {
+			return s, true
+		}
+	}
+	return StreamInfo{}, false
+}
+
+// DisplaySize returns the width and height after applying rotation
+func (s StreamInfo) DisplaySize() (int, int) {
+	if s.Rotation%180 != 0 {
+		return s.Height, s.Width
+	}
+	return s.Width, s.Height
+}
+
+func parseSeconds(value string) time.Duration {
+	seconds, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return 0
+	}
+	return time.Duration(seconds * float64(time.Second))
+}
+
+func parseInt(value string) int64 {
+	n, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return 0
+	}
+	return n
+}
+
+// parseRational parses ffprobe rationals such as "30000/1001"
+func parseRational(value string) float64 {
+	num, den, found := strings.Cut(value, "/")
+	if !found {
+		f, _ := strconv.ParseFloat(value, 64)
+		return f
+	}
+	n, err1 := strconv.ParseFloat(num, 64)
+	d, err2 := strconv.ParseFloat(den, 64)
+	if err1 != nil || err2 != nil || d == 0 {
+		return 0
+	}
+	return n / d
+}
@@ -0,0 +1,33 @@
+package video
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// Probe runs ffprobe at the given path against a media file and parses the result
+func Probe(ctx context.Context, ffprobePath, inputPath string) (MediaInfo, error) {
+	cmd := exec.CommandContext(ctx, ffprobePath,
+		"-v", "error",
+		"-show_format",
+		"-show_streams",
+		"-of", "json",
+		inputPath,
+	)
+
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		if ctx.Err() != nil {
+			return MediaInfo{}, ctx.Err()
+		}
+		return MediaInfo{}, fmt.Errorf("ffprobe failed: %w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+
+	return ParseProbe(stdout.String())
+}
@@ -0,0 +1,64 @@
+package video
+
+import (
+	"bytes"
+	"strconv"
+	"strings"
+	"time"
+
+	ffmpeg "github.com/u2takey/ffmpeg-go"
+)
+
+// ProgressFunc receives how much media time an ffmpeg run has written so far
+type ProgressFunc func(done time.Duration)
+
+// withProgress adds ffmpeg's machine-readable progress reporting on stdout
+// to the output args. ffmpeg accepts these global options anywhere on the
+// command line, so they can go with the output ones.
+func withProgress(args ffmpeg.KwArgs, fn ProgressFunc) ffmpeg.KwArgs {
+	if fn != nil {
+		args["progress"] = "pipe:1"
+		args["nostats"] = ""
+	}
+	return args
+}
+
+// withProgressOutput sends ffmpeg's stdout to a parser calling fn
+func withProgressOutput(s *ffmpeg.Stream, fn ProgressFunc) *ffmpeg.Stream {
+	if fn == nil {
+		return s
+	}
+	return s.WithOutput(&progressWriter{fn: fn})
+}
+
+// progressWriter parses the key=value lines ffmpeg writes with -progress
+type progressWriter struct {
+	fn      ProgressFunc
+	partial []byte
+}
+
+func (w *progressWriter) Write(p []byte) (int, error) {
+	w.partial = append(w.partial, p...)
+	for {
+		i := bytes.IndexByte(w.partial, '\n')
+		if i < 0 {
+			break
+		}
+		w.line(string(w.partial[:i]))
+		w.partial = w.partial[i+1:]
+	}
+	return len(p), nil
+}
+
+func (w *progressWriter) line(line string) {
+	key, value, ok := strings.Cut(strings.TrimSpace(line), "=")
+	// Despite its name, out_time_ms is also in microseconds
+	if !ok || (key != "out_time_us" && key != "out_time_ms") {
+		return
+	}
+	us, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || us < 0 {
+		return // "N/A" until the first frame is written
+	}
+	w.fn(time.Duration(us) * time.Microsecond)
+}
@@ -0,0 +1,219 @@
+package video
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/viper"
+)
+
+// QualityPresetsKey is the config section holding per-preset overrides, e.g.
+//
+//	quality-presets:
+//	  high:
+//	    crf: 20
+const QualityPresetsKey = "quality-presets"
+
+// DefaultQuality is the preset used when none is given
+const DefaultQuality = "medium"
+
+// QualityPresets are the built-in encoder settings for --quality
+var QualityPresets = map[string]EncodeSettings{
+	"low": {
+		VideoCodec:   "libx264",
+		CRF:          28,
+		Preset:       "veryfast",
+		MaxBitrate:   "1500k",
+		AudioCodec:   "aac",
+		AudioBitrate: "96k",
+		MaxHeight:    720,
+	},
+	"medium": {
+		VideoCodec:   "libx264",
+		CRF:          23,
+		Preset:       "veryfast",
+		MaxBitrate:   "4M",
+		AudioCodec:   "aac",
+		AudioBitrate: "128k",
+		MaxHeight:    1080,
+	},
+	"high": {
+		VideoCodec:   "libx264",
+		CRF:          18,
+		Preset:       "medium",
+		MaxBitrate:   "10M",
+		AudioCodec:   "aac",
+		AudioBitrate: "192k",
+	},
+}
+
+// EncodeSettingFields are the keys a preset can override in the config file
+var EncodeSettingFields = []string{
+	"codec", "crf", "preset", "max-bitrate", "audio-codec", "audio-bitrate", "max-height",
+}
+
+// QualityNames lists the built-in presets plus any defined only in the config file
+func QualityNames() []string {
+	seen := map[string]bool{}
+	for name := range QualityPresets {
+		seen[name] = true
+	}
+	for name := range viper.GetStringMap(QualityPresetsKey) {
+		seen[name] = true
+	}
+
+	names := make([]string, 0, len(seen))
+	for name := range seen {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// QualityPreset returns the encoder settings for a quality name with any
+// overrides from the config file applied. Presets that exist only in the
+// config file start from the medium preset.
+func QualityPreset(name string) (EncodeSettings, error) {
+	settings, builtin := QualityPresets[name]
+	overridden := viper.IsSet(QualityPresetsKey + "." + name)
+	if !builtin && !overridden {
+		return EncodeSettings{}, fmt.Errorf("unknown quality %q (use %s)", name, strings.Join(QualityNames(), ", "))
+	}
+	if !builtin {
+		settings = QualityPresets[DefaultQuality]
+	}
+
+	if overridden {
+		if err := viper.UnmarshalKey(QualityPresetsKey+"."+name, &settings); err != nil {
+			return EncodeSettings{}, fmt.Errorf("invalid %s.%s in config: %w", QualityPresetsKey, name, err)
+		}
+	}
+
+	if err := settings.Validate(); err != nil {
+		return EncodeSettings{}, fmt.Errorf("invalid quality preset %q: %w", name, err)
+	}
+	return settings, nil
+}
+
+// Validate checks that the settings describe a usable encode
+func (s EncodeSettings) Validate() error {
+	if s.VideoCodec == "" {
+		return fmt.Errorf("codec must not be empty")
+	}
+	if s.AudioCodec == "" {
+		return fmt.Errorf("audio-codec must not be empty")
+	}
+	if s.CRF < 0 || s.CRF > 51 {
+		return fmt.Errorf("crf must be between 0 and 51, got %d", s.CRF)
+	}
+	if s.MaxHeight < 0 {
+		return fmt.Errorf("max-height must not be negative, got %d", s.MaxHeight)
+	}
+	if s.MaxBitrate != "" {
+		if _, err := ParseBitrate(s.MaxBitrate); err != nil {
+			return fmt.Errorf("max-bitrate: %w", err)
+		}
+	}
+	if s.AudioBitrate != "" {
+		if _, err := ParseBitrate(s.AudioBitrate); err != nil {
+			return fmt.Errorf("audio-bitrate: %w", err)
+		}
+	}
+	return nil
+}
+
+// Fits reports whether a source is already what the preset would produce:
+// the same video and audio codecs, within the resolution cap and within the
+// video and audio bitrate caps. Only then does a stream copy not violate it.
+func (s EncodeSettings) Fits(info MediaInfo) bool {
+	stream, ok := info.VideoStream()
+	if !ok || stream.CodecName != EncoderCodec(s.VideoCodec) {
+		return false
+	}
+	// ffmpeg applies the rotation before scaling, so the cap is on the displayed height
+	if _, height := stream.DisplaySize(); s.MaxHeight > 0 && height > s.MaxHeight {
+		return false
+	}
+	bitRate := stream.BitRate
+	if bitRate == 0 {
+		bitRate = info.BitRate
+	}
+	if !withinBitrate(bitRate, s.MaxBitrate) {
+		return false
+	}
+
+	if audio, ok := info.AudioStream(); ok {
+		if audio.CodecName != EncoderCodec(s.AudioCodec) {
+			return false
+		}
+		if !withinBitrate(audio.BitRate, s.AudioBitrate) {
+			return false
+		}
+	}
+	return true
+}
+
+// withinBitrate reports whether a stream's bitrate is known to be at most
+// limit, an ffmpeg style bitrate. An empty limit allows anything.
+func withinBitrate(bitRate int64, limit string) bool {
+	if limit == "" {
+		return true
+	}
+	ceiling, _ := ParseBitrate(limit)
+	return bitRate > 0 && bitRate <= ceiling
+}
+
+// encoderCodecs maps ffmpeg encoder names to the codec name ffprobe reports
+// for streams they produce
+var encoderCodecs = map[string]string{
+	"libx264":    "h264",
+	"libx265":    "hevc",
+	"libvpx":     "vp8",
+	"libvpx-vp9": "vp9",
+	"libaom-av1": "av1",
+	"libsvtav1":  "av1",
+	"libfdk_aac": "aac",
+	"libopus":    "opus",
+	"libvorbis":  "vorbis",
+	"libmp3lame": "mp3",
+}
+
+// EncoderCodec returns the codec an encoder produces, e.g. h264 for libx264
+// or h264_videotoolbox. Names that are already codecs, such as aac, are
+// returned unchanged.
+func EncoderCodec(encoder string) string {
+	if codec, ok := encoderCodecs[encoder]; ok {
+		return codec
+	}
+	// Hardware encoders are named <codec>_<api>, e.g. hevc_nvenc
+	for _, api := range []string{"_nvenc", "_qsv", "_vaapi", "_videotoolbox", "_amf", "_v4l2m2m"} {
+		if codec, ok := strings.CutSuffix(encoder, api); ok {
+			return codec
+		}
+	}
+	return encoder
+}
+
+// ParseBitrate parses an ffmpeg style bitrate such as "128k" or "4M" into bits
+// per second. Suffixes are case-insensitive.
+func ParseBitrate(s string) (int64, error) {
+	number := strings.TrimSpace(s)
+	multiplier := int64(1)
+	switch {
+	case strings.HasSuffix(number, "k"), strings.HasSuffix(number, "K"):
+		multiplier = 1000
+	case strings.HasSuffix(number, "m"), strings.HasSuffix(number, "M"):
+		multiplier = 1000 * 1000
+	}
+	if multiplier > 1 {
+		number = number[:len(number)-1]
+	}
+
+	value, err := strconv.ParseFloat(number, 64)
+	if err != nil || value <= 0 {
+		return 0, fmt.Errorf("invalid bitrate %q (e.g. 128k, 4M)", s)
+	}
+	return int64(value * float64(multiplier)), nil
+}
@@ -0,0 +1,103 @@
+package video
+
+import "testing"
+
+func TestParseBitrate(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    int64
+		wantErr bool
+	}{
+		{in: "128k", want: 128000},
+		{in: " 96K ", want: 96000},
+		{in: "4M", want: 4000000},
+		{in: "4m", want: 4000000},
+		{in: "1.5M", want: 1500000},
+		{in: "500", want: 500},
+		{in: "", wantErr: true},
+		{in: "k", wantErr: true},
+		{in: "0", wantErr: true},
+		{in: "-1k", wantErr: true},
+		{in: "fast", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := ParseBitrate(tt.in)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseBitrate(%q) error = %v, wantErr %v", tt.in, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseBitrate(%q) = %d, want %d", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEncoderCodec(t *testing.T) {
+	tests := map[string]string{
+		"libx264":           "h264",
+		"libx265":           "hevc",
+		"libvpx-vp9":        "vp9",
+		"hevc_nvenc":        "hevc",
+		"h264_videotoolbox": "h264",
+		"aac":               "aac",
+		"libfdk_aac":        "aac",
+		"copy":              "copy",
+	}
+
+	for encoder, want := range tests {
+		if got := EncoderCodec(encoder); got != want {
+			t.Errorf("EncoderCodec(%q) = %q, want %q", encoder, got, want)
+		}
+	}
+}
+
+func TestFits(t *testing.T) {
+	preset := EncodeSettings{VideoCodec: "libx264", MaxBitrate: "4M", AudioCodec: "aac", AudioBitrate: "128k", MaxHeight: 1080}
+	rotated := func(width, height, rotation int) MediaInfo {
+		return MediaInfo{Streams: []StreamInfo{
+			{CodecType: "video", CodecName: "h264", Width: width, Height: height, Rotation: rotation, BitRate: 3000000},
+			{CodecType: "audio", CodecName: "aac", BitRate: 128000},
+		}}
+	}
+	media := func(videoCodec string, height int, bitRate int64, audioCodec string, audioBitRate int64) MediaInfo {
+		info := MediaInfo{Streams: []StreamInfo{{CodecType: "video", CodecName: videoCodec, Height: height, BitRate: bitRate}}}
+		if audioCodec != "" {
+			info.Streams = append(info.Streams, StreamInfo{CodecType: "audio", CodecName: audioCodec, BitRate: audioBitRate})
+		}
+		return info
+	}
+
+	tests := []struct {
+		name     string
+		settings EncodeSettings
+		info     MediaInfo
+		want     bool
+	}{
+		{"matching source", preset, media("h264", 1080, 3000000, "aac", 128000), true},
+		{"video only", preset, media("h264", 720, 3000000, "", 0), true},
+		{"different video codec", preset, media("hevc", 1080, 3000000, "aac", 128000), false},
+		{"taller than the cap", preset, media("h264", 2160, 3000000, "aac", 128000), false},
+		{"video bitrate over the cap", preset, media("h264", 1080, 8000000, "aac", 128000), false},
+		{"unknown video bitrate", preset, media("h264", 1080, 0, "aac", 128000), false},
+		{"different audio codec", preset, media("h264", 1080, 3000000, "opus", 96000), false},
+		{"audio bitrate over the preset", preset, media("h264", 1080, 3000000, "aac", 320000), false},
+		{"no caps", EncodeSettings{VideoCodec: "libx264", AudioCodec: "aac"}, media("h264", 2160, 0, "aac", 0), true},
+		{"no video stream", preset, MediaInfo{}, false},
+		{"landscape within the cap", preset, rotated(1920, 1080, 0), true},
+		{"rotated phone video taller than the cap", preset, rotated(1920, 1080, 90), false},
+		{"rotated the other way", preset, rotated(1920, 1080, -90), false},
+		{"upside down within the cap", preset, rotated(1920, 1080, 180), true},
+		{"rotated into the cap", preset, rotated(1080, 1920, 270), true},
+		{"lowercase bitrate cap", EncodeSettings{VideoCodec: "libx264", MaxBitrate: "4m", AudioCodec: "aac"}, media("h264", 1080, 3000000, "aac", 128000), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.settings.Fits(tt.info); got != tt.want {
+				t.Errorf("Fits() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
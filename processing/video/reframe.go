@@ -0,0 +1,132 @@
+package video
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ReframeMode selects how a clip is fitted to a different aspect ratio
+type ReframeMode string
+
+const (
+	// ReframeNone keeps the source's aspect ratio
+	ReframeNone ReframeMode = ""
+	// ReframeCenter crops a fixed window from the middle of the frame
+	ReframeCenter ReframeMode = "center"
+	// ReframeBlurPad fits the whole frame inside the target and fills the rest
+	// with a blurred, zoomed copy of it
+	ReframeBlurPad ReframeMode = "blur-pad"
+	// ReframeTrack crops a window that follows the most active region of the frame
+	ReframeTrack ReframeMode = "track"
+)
+
+// blurPadStrength is the boxblur radius and power of the blur-pad background
+const blurPadStrength = "20:3"
+
+// ParseReframeMode validates a --reframe value
+func ParseReframeMode(s string) (ReframeMode, error) {
+	switch mode := ReframeMode(strings.ToLower(strings.TrimSpace(s))); mode {
+	case ReframeNone, "none":
+		return ReframeNone, nil
+	case ReframeCenter, ReframeBlurPad, ReframeTrack:
+		return mode, nil
+	default:
+		return "", fmt.Errorf("unknown reframe mode %q (use center, blur-pad or track)", s)
+	}
+}
+
+// Aspect is a width:height ratio such as 9:16
+type Aspect struct {
+	Width  int `json:"width"`
+	Height int `json:"height"`
+}
+
+// ParseAspect parses a ratio written as W:H, e.g. 9:16, 1:1 or 4:5
+func ParseAspect(s string) (Aspect, error) {
+	w, h, ok := strings.Cut(strings.TrimSpace(s), ":")
+	if !ok {
+		return Aspect{}, fmt.Errorf("invalid aspect ratio %q (use W:H, e.g. 9:16)", s)
+	}
+	width, errW := strconv.Atoi(w)
+	height, errH := strconv.Atoi(h)
+	if errW != nil || errH != nil || width <= 0 || height <= 0 {
+		return Aspect{}, fmt.Errorf("invalid aspect ratio %q (use W:H, e.g. 9:16)", s)
+	}
+	return Aspect{Width: width, Height: height}, nil
+}
+
+func (a Aspect) String() string {
+	return fmt.Sprintf("%d:%d", a.Width, a.Height)
+}
+
+// Ratio returns width divided by height
+func (a Aspect) Ratio() float64 {
+	return float64(a.Width) / float64(a.Height)
+}
+
+// ReframeOptions configures reframing during clip extraction
+type ReframeOptions struct {
+	Mode   ReframeMode `json:"mode"`
+	Aspect Aspect      `json:"aspect"`
+}
+
+// Enabled reports whether the clip's aspect ratio is changed
+func (r ReframeOptions) Enabled() bool {
+	return r.Mode != ReframeNone
+}
+
+// CropSize is the largest window of the target aspect ratio that fits in a
+// width x height frame
+func (r ReframeOptions) CropSize(width, height int) (int, int) {
+	if float64(width)/float64(height) > r.Aspect.Ratio() {
+		return even(float64(height) * r.Aspect.Ratio()), even(float64(height))
+	}
+	return even(float64(width)), even(float64(width) / r.Aspect.Ratio())
+}
+
+// OutputSize is the frame size a reframed width x height source produces,
+// before any quality preset scaling
+func (r ReframeOptions) OutputSize(width, height int) (int, int) {
+	switch r.Mode {
+	case ReframeNone:
+		return width, height
+	case ReframeBlurPad:
+		// The smallest canvas of the target ratio whose long side matches the
+		// source's, so a 1920x1080 source becomes 1080x1920
+		long := max(width, height)
+		if r.Aspect.Ratio() < 1 {
+			return even(float64(long) * r.Aspect.Ratio()), even(float64(long))
+		}
+		return even(float64(long)), even(float64(long) / r.Aspect.Ratio())
+	default:
+		return r.CropSize(width, height)
+	}
+}
+
+// filter returns the filtergraph fitting a width x height source to the
+// target aspect ratio. commands optionally names a sendcmd file that moves
+// the crop window over time.
+func (r ReframeOptions) filter(width, height int, commands string) string {
+	switch r.Mode {
+	case ReframeBlurPad:
+		w, h := r.OutputSize(width, height)
+		return fmt.Sprintf("split[bg][fg];"+
+			"[bg]scale=%[1]d:%[2]d:force_original_aspect_ratio=increase,crop=%[1]d:%[2]d,boxblur=%[3]s[blurred];"+
+			"[fg]scale=%[1]d:%[2]d:force_original_aspect_ratio=decrease[fitted];"+
+			"[blurred][fitted]overlay=(W-w)/2:(H-h)/2,setsar=1",
+			w, h, blurPadStrength)
+	default:
+		w, h := r.CropSize(width, height)
+		crop := fmt.Sprintf("crop@reframe=%d:%d:%d:%d", w, h, (width-w)/2, (height-h)/2)
+		if commands != "" {
+			crop = "sendcmd=f=" + escapeFilterArg(commands) + "," + crop
+		}
+		return crop + ",setsar=1"
+	}
+}
+
+// even rounds down to an even number of pixels, as yuv420p requires
+func even(f float64) int {
+	return int(f) &^ 1
+}
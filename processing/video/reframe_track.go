@@ -0,0 +1,219 @@
+package video
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Settings for ReframeTrack's frame analysis and crop motion
+const (
+	trackSampleRate  = 4    // Frames analysed per second
+	trackSampleWidth = 160  // Width frames are downscaled to for analysis
+	trackCommandRate = 15   // Crop position updates per second
+	trackMaxSpeed    = 0.35 // Fastest pan, in source widths per second
+	trackDeadZone    = 0.05 // Moves smaller than this fraction of the source width are ignored
+	trackSmoothing   = time.Second
+	trackMotionGain  = 2 // Weight of change between frames against detail within a frame
+)
+
+// trackPosition is the crop window's left edge, in source pixels, at a time
+// relative to the start of the clip
+type trackPosition struct {
+	At time.Duration
+	X  float64
+}
+
+// writeTrackCommands analyses the segment and writes a sendcmd script that
+// pans the reframe crop across a width x height source to follow the most
+// active region. It returns "" when the crop can't move horizontally.
+func (ve *VideoExtractor) writeTrackCommands(ctx context.Context, inputPath string, start, end time.Duration, reframe ReframeOptions, width, height int) (string, error) {
+	cropW, _ := reframe.CropSize(width, height)
+	if cropW >= width {
+		return "", nil
+	}
+
+	positions, err := ve.trackSubject(ctx, inputPath, start, end, width, height, cropW)
+	if err != nil {
+		return "", err
+	}
+	if len(positions) == 0 {
+		return "", nil
+	}
+
+	path, err := newTempFile(ve.Workspace, ve.TempDir, "reframe", ".cmd")
+	if err != nil {
+		return "", err
+	}
+
+	var script strings.Builder
+	step := time.Second / trackCommandRate
+	for at := time.Duration(0); at < end-start; at += step {
+		fmt.Fprintf(&script, "%.3f crop@reframe x %d;\n", at.Seconds(), int(interpolate(positions, at)))
+	}
+	if err := os.WriteFile(path, []byte(script.String()), 0644); err != nil {
+		return "", fmt.Errorf("failed to write reframe commands: %w", err)
+	}
+	return path, nil
+}
+
+// trackSubject samples low resolution grayscale frames and finds, for each,
+// the cropW wide window with the most detail and motion, then smooths the
+// window's path
+func (ve *VideoExtractor) trackSubject(ctx context.Context, inputPath string, start, end time.Duration, width, height, cropW int) ([]trackPosition, error) {
+	sampleH := max(even(float64(trackSampleWidth)*float64(height)/float64(width)), 2)
+	frameSize := trackSampleWidth * sampleH
+	scale := float64(width) / trackSampleWidth
+	window := max(int(float64(cropW)/scale), 1)
+
+	frames, errs := ve.SampleFrames(ctx, inputPath, FrameSampleOptions{
+		Interval:    time.Second / trackSampleRate,
+		Start:       start,
+		End:         end,
+		Width:       trackSampleWidth,
+		Height:      sampleH,
+		PixelFormat: PixelGray,
+	}, 1)
+
+	var prev []byte
+	var raw []trackPosition
+	last := float64(trackSampleWidth-window) / 2
+	for frame := range frames {
+		if len(frame.Pix) != frameSize {
+			continue
+		}
+		if left, ok := salientWindow(frame.Pix, prev, trackSampleWidth, sampleH, window); ok {
+			last = float64(left)
+		}
+		raw = append(raw, trackPosition{
+			At: frame.Timestamp - start,
+			X:  last * scale,
+		})
+		prev = frame.Pix
+	}
+	if err := <-errs; err != nil {
+		if ctx.Err() != nil {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to analyse frames for tracking: %w", err)
+	}
+
+	return smoothTrack(raw, float64(width), float64(width-cropW)), nil
+}
+
+// salientWindow scores each column of a grayscale frame by its edges and its
+// change since the previous frame, finds the window with the highest total
+// and returns its left edge once centred on that activity. Frames with
+// nothing to follow report false.
+func salientWindow(frame, prev []byte, width, height, window int) (int, bool) {
+	columns := make([]int, width)
+	total := 0
+	for y := 0; y < height-1; y++ {
+		row := frame[y*width : (y+1)*width]
+		below := frame[(y+1)*width : (y+2)*width]
+		for x := 0; x < width-1; x++ {
+			p := int(row[x])
+			score := abs(int(row[x+1])-p) + abs(int(below[x])-p)
+			if prev != nil {
+				score += trackMotionGain * abs(p-int(prev[y*width+x]))
+			}
+			columns[x] += score
+			total += score
+		}
+	}
+
+	// Flat or static frames, such as fades to black, don't move the crop
+	if total < width*height {
+		return 0, false
+	}
+
+	sum := 0
+	for x := 0; x < window; x++ {
+		sum += columns[x]
+	}
+	best, bestLeft := sum, 0
+	for left := 1; left+window <= width; left++ {
+		sum += columns[left+window-1] - columns[left-1]
+		if sum > best {
+			best, bestLeft = sum, left
+		}
+	}
+
+	// Centre the window on the activity inside it rather than leaving it at
+	// an edge of the window
+	weighted := 0
+	for x := bestLeft; x < bestLeft+window; x++ {
+		weighted += x * columns[x]
+	}
+	if best == 0 {
+		return bestLeft, true
+	}
+	centre := weighted / best
+	return max(0, min(centre-window/2, width-window)), true
+}
+
+// smoothTrack turns raw per-frame positions into steady camera motion: a
+// median removes one-off outliers, a dead zone holds the crop still through
+// small shifts, pans are limited to trackMaxSpeed and a moving average
+// eases them in and out. Positions are clamped to [0, maxX].
+func smoothTrack(raw []trackPosition, width, maxX float64) []trackPosition {
+	if len(raw) == 0 {
+		return nil
+	}
+
+	xs := make([]float64, len(raw))
+	for i := range raw {
+		lo, hi := max(i-2, 0), min(i+3, len(raw))
+		window := make([]float64, hi-lo)
+		for j := lo; j < hi; j++ {
+			window[j-lo] = raw[j].X
+		}
+		sort.Float64s(window)
+		xs[i] = window[len(window)/2]
+	}
+
+	held := xs[0]
+	maxStep := trackMaxSpeed * width / trackSampleRate
+	for i, x := range xs {
+		if d := x - held; d > trackDeadZone*width || d < -trackDeadZone*width {
+			held += max(-maxStep, min(d, maxStep))
+		}
+		xs[i] = held
+	}
+
+	radius := int(trackSmoothing.Seconds() * trackSampleRate / 2)
+	smoothed := make([]trackPosition, len(raw))
+	for i := range raw {
+		lo, hi := max(i-radius, 0), min(i+radius+1, len(raw))
+		sum := 0.0
+		for j := lo; j < hi; j++ {
+			sum += xs[j]
+		}
+		smoothed[i] = trackPosition{At: raw[i].At, X: max(0, min(sum/float64(hi-lo), maxX))}
+	}
+	return smoothed
+}
+
+// interpolate returns the position at a time between samples
+func interpolate(positions []trackPosition, at time.Duration) float64 {
+	i := sort.Search(len(positions), func(i int) bool { return positions[i].At > at })
+	switch {
+	case i == 0:
+		return positions[0].X
+	case i == len(positions):
+		return positions[len(positions)-1].X
+	}
+	a, b := positions[i-1], positions[i]
+	f := float64(at-a.At) / float64(b.At-a.At)
+	return a.X + f*(b.X-a.X)
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
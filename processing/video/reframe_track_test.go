@@ -0,0 +1,60 @@
+package video
+
+import (
+	"math"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// track builds positions sampled at trackSampleRate
+func track(xs ...float64) []trackPosition {
+	positions := make([]trackPosition, len(xs))
+	for i, x := range xs {
+		positions[i] = trackPosition{At: time.Duration(i) * time.Second / trackSampleRate, X: x}
+	}
+	return positions
+}
+
+func TestSmoothTrack(t *testing.T) {
+	tests := []struct {
+		name  string
+		raw   []trackPosition
+		width float64
+		maxX  float64
+		want  []trackPosition
+	}{
+		{"no samples", nil, 100, 50, nil},
+		{"steady subject", track(20, 20, 20, 20, 20), 100, 50, track(20, 20, 20, 20, 20)},
+		{"one-off outlier removed", track(10, 10, 90, 10, 10), 100, 100, track(10, 10, 10, 10, 10)},
+		{"jitter inside the dead zone ignored", track(20, 23, 20, 23, 20), 100, 50, track(20, 20, 20, 20, 20)},
+		{"clamped to the frame", track(80, 80, 80), 100, 50, track(50, 50, 50)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := smoothTrack(tt.raw, tt.width, tt.maxX); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("smoothTrack() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSmoothTrackPanSpeed(t *testing.T) {
+	const width = 100.0
+	raw := track(0, 0, 0, 0, 100, 100, 100, 100, 100, 100, 100, 100, 100, 100, 100, 100)
+	got := smoothTrack(raw, width, width)
+
+	maxStep := trackMaxSpeed * width / trackSampleRate
+	for i := 1; i < len(got); i++ {
+		if step := math.Abs(got[i].X - got[i-1].X); step > maxStep+1e-9 {
+			t.Errorf("pan of %.2f between samples %d and %d, want at most %.2f", step, i-1, i, maxStep)
+		}
+	}
+	if got[0].X != 0 {
+		t.Errorf("track starts at %.2f, want 0", got[0].X)
+	}
+	if last := got[len(got)-1].X; last <= got[0].X {
+		t.Errorf("track never pans towards the subject, ends at %.2f", last)
+	}
+}
@@ -0,0 +1,38 @@
+package video
+
+import (
+	"fmt"
+	"os"
+
+	"ai-video-editor/processing/workspace"
+)
+
+// newTempFile reserves a uniquely named file for an extraction. Files are
+// allocated through the job workspace when there is one so they appear in its
+// manifest; otherwise they go straight into dir.
+func newTempFile(ws *workspace.Workspace, dir, kind, ext string) (string, error) {
+	if ws != nil {
+		return ws.NewFile(kind, ext)
+	}
+
+	// Ensure temp directory exists
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", fmt.Errorf("failed to create temp directory: %w", err)
+	}
+
+	f, err := os.CreateTemp(dir, kind+"_*"+ext)
+	if err != nil {
+		return "", fmt.Errorf("failed to create temp file: %w", err)
+	}
+	f.Close()
+	return f.Name(), nil
+}
+
+// removeTempFile deletes a file created by newTempFile, dropping it from the
+// workspace manifest when there is one
+func removeTempFile(ws *workspace.Workspace, path string) error {
+	if ws != nil {
+		return ws.Remove(path)
+	}
+	return os.Remove(path)
+}
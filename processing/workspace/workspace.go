@@ -0,0 +1,169 @@
+package workspace
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ManifestFile is the name of the artifact manifest written into each workspace
+const ManifestFile = "manifest.json"
+
+// Artifact is a file produced inside a workspace
+type Artifact struct {
+	Path      string    `json:"path"`
+	Kind      string    `json:"kind"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Workspace is a private directory for a single processing job. Every
+// temporary file a job produces lives inside it so concurrent jobs never
+// collide and cleanup is a single directory removal.
+type Workspace struct {
+	ID   string
+	Dir  string
+	Keep bool
+
+	mu        sync.Mutex
+	artifacts []Artifact
+	cleaned   bool
+}
+
+// New creates a fresh workspace directory under baseDir. When keep is true
+// Cleanup leaves the directory in place for debugging.
+func New(baseDir string, keep bool) (*Workspace, error) {
+	if baseDir == "" {
+		baseDir = os.TempDir()
+	}
+
+	id, err := NewID()
+	if err != nil {
+		return nil, err
+	}
+
+	dir := filepath.Join(baseDir, "job-"+id)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create workspace directory: %w", err)
+	}
+
+	return &Workspace{ID: id, Dir: dir, Keep: keep}, nil
+}
+
+// NewID returns a sortable, collision-resistant identifier such as
+// 20250808-142501-3f9a1c2b
+func NewID() (string, error) {
+	suffix := make([]byte, 4)
+	if _, err := rand.Read(suffix); err != nil {
+		return "", fmt.Errorf("failed to generate workspace id: %w", err)
+	}
+	return time.Now().Format("20060102-150405") + "-" + hex.EncodeToString(suffix), nil
+}
+
+// NewFile reserves a uniquely named file in the workspace and records it in
+// the manifest. kind is used as the file name prefix (e.g. "audio").
+func (w *Workspace) NewFile(kind, ext string) (string, error) {
+	if ext != "" && !strings.HasPrefix(ext, ".") {
+		ext = "." + ext
+	}
+
+	f, err := os.CreateTemp(w.Dir, kind+"_*"+ext)
+	if err != nil {
+		return "", fmt.Errorf("failed to create %s file in workspace: %w", kind, err)
+	}
+	f.Close()
+
+	w.Track(f.Name(), kind)
+	return f.Name(), nil
+}
+
+// NewDir creates a uniquely named subdirectory in the workspace
+func (w *Workspace) NewDir(kind string) (string, error) {
+	dir, err := os.MkdirTemp(w.Dir, kind+"_*")
+	if err != nil {
+		return "", fmt.Errorf("failed to create %s directory in workspace: %w", kind, err)
+	}
+
+	w.Track(dir, kind)
+	return dir, nil
+}
+
+// Track records an artifact that was created by other means
+func (w *Workspace) Track(path, kind string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.artifacts = append(w.artifacts, Artifact{
+		Path:      path,
+		Kind:      kind,
+		CreatedAt: time.Now(),
+	})
+}
+
+// Remove deletes an artifact early and drops it from the manifest
+func (w *Workspace) Remove(path string) error {
+	w.mu.Lock()
+	for i, a := range w.artifacts {
+		if a.Path == path {
+			w.artifacts = append(w.artifacts[:i], w.artifacts[i+1:]...)
+			break
+		}
+	}
+	w.mu.Unlock()
+
+	if err := os.RemoveAll(path); err != nil {
+		return fmt.Errorf("failed to remove %s: %w", path, err)
+	}
+	return nil
+}
+
+// Artifacts returns a snapshot of the manifest
+func (w *Workspace) Artifacts() []Artifact {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	return append([]Artifact(nil), w.artifacts...)
+}
+
+// WriteManifest saves the artifact list as manifest.json in the workspace
+func (w *Workspace) WriteManifest() error {
+	data, err := json.MarshalIndent(struct {
+		ID        string     `json:"id"`
+		Artifacts []Artifact `json:"artifacts"`
+	}{w.ID, w.Artifacts()}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode manifest: %w", err)
+	}
+
+	if err := os.WriteFile(filepath.Join(w.Dir, ManifestFile), data, 0644); err != nil {
+		return fmt.Errorf("failed to write manifest: %w", err)
+	}
+	return nil
+}
+
+// Cleanup removes the workspace directory unless Keep is set, in which case
+// the manifest is written so the leftovers can be inspected. It is safe to
+// call more than once.
+func (w *Workspace) Cleanup() error {
+	w.mu.Lock()
+	if w.cleaned {
+		w.mu.Unlock()
+		return nil
+	}
+	w.cleaned = true
+	w.mu.Unlock()
+
+	if w.Keep {
+		return w.WriteManifest()
+	}
+
+	if err := os.RemoveAll(w.Dir); err != nil {
+		return fmt.Errorf("failed to remove workspace: %w", err)
+	}
+	return nil
+}